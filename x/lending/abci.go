@@ -5,13 +5,25 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
-// BeginBlocker check for infraction evidence or downtime of validators
-// on every begin block
+// BeginBlocker called every block, migrates the debt indexes and archive of older
+// stores, accrues the interest of the debts and of the credit lines whose
+// accrual period has elapsed and then moves the rates of the pools with a rate
+// model to those of their utilization
 func BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock, k Keeper) {
-// 	TODO: fill out if your application requires beginblock, if not you can delete this function
+	k.MigrateDebtIndexes(ctx)
+	k.MigrateDebtArchive(ctx)
+	k.AccrueInterest(ctx)
+	k.AccrueCreditLineInterest(ctx)
+	k.UpdatePoolRates(ctx)
 }
 
-// EndBlocker called every block, process inflation, update validator set.
+// EndBlocker called every block, discards the debt proposals that have expired,
+// marks as overdue or defaulted the debts whose deadlines have passed, marks as
+// late their unpaid installments past due, charging the late fees of both, and
+// prunes the archived debts older than the retention period
 func EndBlocker(ctx sdk.Context, k Keeper) {
-// 	TODO: fill out if your application requires endblock, if not you can delete this function
+	k.RemoveExpiredProposals(ctx)
+	k.UpdateDebtStatuses(ctx)
+	k.UpdateInstallmentStatuses(ctx)
+	k.PruneArchive(ctx)
 }
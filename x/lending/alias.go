@@ -25,9 +25,53 @@ var (
 	// variable aliases
 	ModuleCdc = types.ModuleCdc
 
-	NewMsgCreateDebt = types.NewMsgCreateDebt
-	NewMsgPayDebt    = types.NewMsgPayDebt
-	NewMsgChangeDebt = types.NewMsgChangeDebt
+	NewMsgCreateDebt    = types.NewMsgCreateDebt
+	NewMsgPayDebt       = types.NewMsgPayDebt
+	NewMsgPayDebtInFull = types.NewMsgPayDebtInFull
+	NewMsgChangeDebt    = types.NewMsgChangeDebt
+	NewArchivedDebt     = types.NewArchivedDebt
+
+	NewMsgProposeDebt      = types.NewMsgProposeDebt
+	NewMsgAcceptDebt       = types.NewMsgAcceptDebt
+	NewMsgRejectDebt       = types.NewMsgRejectDebt
+	NewMsgWithdrawProposal = types.NewMsgWithdrawProposal
+	NewMsgClaimCollateral  = types.NewMsgClaimCollateral
+
+	NewParams       = types.NewParams
+	DefaultParams   = types.DefaultParams
+	NewMsgPostPrice = types.NewMsgPostPrice
+	NewMsgLiquidate = types.NewMsgLiquidate
+
+	NewMsgTransferDebt    = types.NewMsgTransferDebt
+	NewMsgConsentTransfer = types.NewMsgConsentTransfer
+	NewDebtTransfer       = types.NewDebtTransfer
+
+	NewScheduleTerms       = types.NewScheduleTerms
+	NewCustomScheduleTerms = types.NewCustomScheduleTerms
+	NewInstallmentDue      = types.NewInstallmentDue
+
+	NewLateFeeTerms = types.NewLateFeeTerms
+	NoLateFees      = types.NoLateFees
+
+	NewCreditLine           = types.NewCreditLine
+	NewMsgOpenCreditLine    = types.NewMsgOpenCreditLine
+	NewMsgDrawCredit        = types.NewMsgDrawCredit
+	NewMsgRepayCredit       = types.NewMsgRepayCredit
+	NewMsgRepayCreditInFull = types.NewMsgRepayCreditInFull
+	NewMsgCloseCreditLine   = types.NewMsgCloseCreditLine
+	NewPositions            = types.NewPositions
+
+	NewPool                = types.NewPool
+	PoolAddress            = types.PoolAddress
+	NewPoolLoan            = types.NewPoolLoan
+	NewMsgCreatePool       = types.NewMsgCreatePool
+	NewMsgDepositToPool    = types.NewMsgDepositToPool
+	NewMsgWithdrawFromPool = types.NewMsgWithdrawFromPool
+	NewMsgBorrowFromPool   = types.NewMsgBorrowFromPool
+
+	NewLinearRateModel = types.NewLinearRateModel
+	NewJumpRateModel   = types.NewJumpRateModel
+	CalculateRates     = types.CalculateRates
 )
 
 type (
@@ -38,4 +82,51 @@ type (
 	MsgCreateDebt = types.MsgCreateDebt
 	MsgPayDebt    = types.MsgPayDebt
 	MsgChangeDebt = types.MsgChangeDebt
-)
\ No newline at end of file
+
+	DebtProposal        = types.DebtProposal
+	MsgProposeDebt      = types.MsgProposeDebt
+	MsgAcceptDebt       = types.MsgAcceptDebt
+	MsgRejectDebt       = types.MsgRejectDebt
+	MsgWithdrawProposal = types.MsgWithdrawProposal
+	MsgClaimCollateral  = types.MsgClaimCollateral
+
+	PostedPrice  = types.PostedPrice
+	CurrentPrice = types.CurrentPrice
+	MsgPostPrice = types.MsgPostPrice
+	MsgLiquidate = types.MsgLiquidate
+
+	ArchivedDebt = types.ArchivedDebt
+	Settlement   = types.Settlement
+	LedgerEntry  = types.LedgerEntry
+	EntryType    = types.EntryType
+
+	MsgTransferDebt    = types.MsgTransferDebt
+	MsgConsentTransfer = types.MsgConsentTransfer
+	DebtTransfer       = types.DebtTransfer
+
+	ScheduleTerms  = types.ScheduleTerms
+	Schedule       = types.Schedule
+	Installment    = types.Installment
+	InstallmentDue = types.InstallmentDue
+
+	LateFeeTerms = types.LateFeeTerms
+
+	CreditLine         = types.CreditLine
+	MsgOpenCreditLine  = types.MsgOpenCreditLine
+	MsgDrawCredit      = types.MsgDrawCredit
+	MsgRepayCredit     = types.MsgRepayCredit
+	MsgCloseCreditLine = types.MsgCloseCreditLine
+	Positions          = types.Positions
+
+	Pool                = types.Pool
+	PoolState           = types.PoolState
+	PoolDeposit         = types.PoolDeposit
+	PoolLoan            = types.PoolLoan
+	MsgCreatePool       = types.MsgCreatePool
+	MsgDepositToPool    = types.MsgDepositToPool
+	MsgWithdrawFromPool = types.MsgWithdrawFromPool
+	MsgBorrowFromPool   = types.MsgBorrowFromPool
+
+	RateModel = types.RateModel
+	PoolRates = types.PoolRates
+)
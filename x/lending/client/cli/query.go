@@ -5,10 +5,29 @@ import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/spoto/lending/x/lending/types"
 )
 
+const (
+	flagStatus       = "status"
+	flagDenom        = "denom"
+	flagMinAmount    = "min-amount"
+	flagMaxAmount    = "max-amount"
+	flagCounterparty = "counterparty"
+	flagPage         = "page"
+	flagLimit        = "limit"
+
+	flagModel              = "model"
+	flagBaseRate           = "base-rate"
+	flagSlope              = "slope"
+	flagOptimalUtilization = "optimal-utilization"
+	flagJumpSlope          = "jump-slope"
+	flagBlocksPerYear      = "blocks-per-year"
+)
+
 func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                        types.ModuleName,
@@ -19,16 +38,91 @@ func GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 	}
 
 	cmd.AddCommand(
+		getDebt(cdc),
 		getAllDebts(cdc),
 		getDebtorDebts(cdc),
 		getCreditorDebts(cdc),
+		getDebtHistory(cdc),
+		getDebtLedger(cdc),
+		getDebtSchedule(cdc),
+		getAllProposals(cdc),
+		getDebtorProposals(cdc),
+		getCreditorProposals(cdc),
+		getTransfers(cdc),
+		getCreditLine(cdc),
+		getAllCreditLines(cdc),
+		getBorrowerCreditLines(cdc),
+		getLenderCreditLines(cdc),
+		getPositions(cdc),
+		getPool(cdc),
+		getAllPools(cdc),
+		getPoolDeposits(cdc),
+		getPoolRates(cdc),
+		calculateRates(cdc),
+		getPrice(cdc),
+		getParams(cdc),
 	)
 
 	return cmd
 }
 
+// addQueryDebtsFlags adds the pagination and the filters of the debts queries
+func addQueryDebtsFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagStatus, "", "only show the debts with this status (active|overdue|defaulted|repaid|forgiven|liquidated)")
+	cmd.Flags().String(flagDenom, "", "only show the debts in this denom")
+	cmd.Flags().String(flagMinAmount, "", "only show the debts of at least this amount")
+	cmd.Flags().String(flagMaxAmount, "", "only show the debts of at most this amount")
+	cmd.Flags().String(flagCounterparty, "", "only show the debts with this address as the other party")
+	cmd.Flags().Int(flagPage, 1, "page of the results")
+	cmd.Flags().Int(flagLimit, types.DefaultQueryLimit, "number of results per page")
+}
+
+// queryDebtsParamsFromFlags yields the JSON of the query params set through the flags
+func queryDebtsParamsFromFlags(cdc *codec.Codec) ([]byte, error) {
+	params := types.NewQueryDebtsParams(viper.GetInt(flagPage), viper.GetInt(flagLimit))
+	params.Denom = viper.GetString(flagDenom)
+
+	if status := viper.GetString(flagStatus); status != "" {
+		debtStatus, err := types.DebtStatusFromString(status)
+		if err != nil {
+			return nil, err
+		}
+		params.Status = debtStatus
+	}
+
+	if minAmount := viper.GetString(flagMinAmount); minAmount != "" {
+		amount, ok := sdk.NewIntFromString(minAmount)
+		if !ok {
+			return nil, fmt.Errorf("invalid min amount %s", minAmount)
+		}
+		params.MinAmount = amount
+	}
+
+	if maxAmount := viper.GetString(flagMaxAmount); maxAmount != "" {
+		amount, ok := sdk.NewIntFromString(maxAmount)
+		if !ok {
+			return nil, fmt.Errorf("invalid max amount %s", maxAmount)
+		}
+		params.MaxAmount = amount
+	}
+
+	if counterparty := viper.GetString(flagCounterparty); counterparty != "" {
+		address, err := sdk.AccAddressFromBech32(counterparty)
+		if err != nil {
+			return nil, err
+		}
+		params.Counterparty = address
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cdc.MarshalJSON(params)
+}
+
 func getDebtorDebts(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "get-debtor-debts [user-address]",
 		Short: "Get all the debts where address is debtor",
 		Args:  cobra.ExactArgs(1),
@@ -36,13 +130,22 @@ func getDebtorDebts(cdc *codec.Codec) *cobra.Command {
 			return getDebtorDebtsFunc(cmd, args, cdc)
 		},
 	}
+
+	addQueryDebtsFlags(cmd)
+
+	return cmd
 }
 
 func getDebtorDebtsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
 	cliCtx := context.NewCLIContext().WithCodec(cdc)
 
+	bz, err := queryDebtsParamsFromFlags(cdc)
+	if err != nil {
+		return err
+	}
+
 	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtorDebts, args[0])
-	res, _, err := cliCtx.QueryWithData(route, nil)
+	res, _, err := cliCtx.QueryWithData(route, bz)
 
 	if err != nil {
 		return err
@@ -54,7 +157,7 @@ func getDebtorDebtsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) err
 }
 
 func getCreditorDebts(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "get-creditor-debts [user-address]",
 		Short: "Get all the debts where address is creditor",
 		Args:  cobra.ExactArgs(1),
@@ -62,12 +165,47 @@ func getCreditorDebts(cdc *codec.Codec) *cobra.Command {
 			return getCreditorDebtsFunc(cmd, args, cdc)
 		},
 	}
+
+	addQueryDebtsFlags(cmd)
+
+	return cmd
 }
 
 func getCreditorDebtsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
 	cliCtx := context.NewCLIContext().WithCodec(cdc)
 
+	bz, err := queryDebtsParamsFromFlags(cdc)
+	if err != nil {
+		return err
+	}
+
 	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryCreditorDebts, args[0])
+	res, _, err := cliCtx.QueryWithData(route, bz)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getDebt(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-debt [id]",
+		Short: "Get the debt with the given ID",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getDebtFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getDebtFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebt, args[0])
 	res, _, err := cliCtx.QueryWithData(route, nil)
 
 	if err != nil {
@@ -79,8 +217,101 @@ func getCreditorDebtsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) e
 	return nil
 }
 
-func getAllDebts(cdc *codec.Codec) *cobra.Command {
+func getDebtLedger(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-debt-ledger [id]",
+		Short: "Get the payments, forgiveness, interest and fees recorded for the debt with the given ID",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getDebtLedgerFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd.Flags().Int(flagPage, 1, "page of the results")
+	cmd.Flags().Int(flagLimit, types.DefaultQueryLimit, "number of results per page")
+
+	return cmd
+}
+
+func getDebtLedgerFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	params := types.NewQueryDebtsParams(viper.GetInt(flagPage), viper.GetInt(flagLimit))
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	bz, err := cdc.MarshalJSON(params)
+	if err != nil {
+		return err
+	}
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtLedger, args[0])
+	res, _, err := cliCtx.QueryWithData(route, bz)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getDebtSchedule(cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
+		Use:   "get-debt-schedule [id]",
+		Short: "Get the installments of the debt with the given ID, with what is paid and what remains of each",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getDebtScheduleFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getDebtScheduleFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtSchedule, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getTransfers(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-transfers",
+		Short: "Get the debt transfers waiting for the consent of the debtor",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getTransfersFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getTransfersFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryTransfers)
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getAllDebts(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
 		Use:   "get-debts",
 		Short: "Get all the debts",
 		Args:  cobra.ExactArgs(0),
@@ -88,12 +319,216 @@ func getAllDebts(cdc *codec.Codec) *cobra.Command {
 			return getAllDebtsFunc(cmd, args, cdc)
 		},
 	}
+
+	addQueryDebtsFlags(cmd)
+
+	return cmd
 }
 
 func getAllDebtsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
 	cliCtx := context.NewCLIContext().WithCodec(cdc)
 
+	bz, err := queryDebtsParamsFromFlags(cdc)
+	if err != nil {
+		return err
+	}
+
 	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllDebts)
+	res, _, err := cliCtx.QueryWithData(route, bz)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getDebtHistory(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-debt-history [user-address]",
+		Short: "Get the closed debts in the archive, all of them or only those of an address",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getDebtHistoryFunc(cmd, args, cdc)
+		},
+	}
+
+	addQueryDebtsFlags(cmd)
+
+	return cmd
+}
+
+func getDebtHistoryFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	bz, err := queryDebtsParamsFromFlags(cdc)
+	if err != nil {
+		return err
+	}
+
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDebtHistory)
+	if len(args) == 1 {
+		route = fmt.Sprintf("%s/%s", route, args[0])
+	}
+
+	res, _, err := cliCtx.QueryWithData(route, bz)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getDebtorProposals(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-debtor-proposals [user-address]",
+		Short: "Get all the pending proposals where address is debtor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getDebtorProposalsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getDebtorProposalsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtorProposals, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getCreditorProposals(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-creditor-proposals [user-address]",
+		Short: "Get all the pending proposals where address is creditor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getCreditorProposalsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getCreditorProposalsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryCreditorProposals, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getAllProposals(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-proposals",
+		Short: "Get all the pending proposals",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getAllProposalsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getAllProposalsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllProposals)
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getCreditLine(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-credit-line [id]",
+		Short: "Get the open credit line with the given ID",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getCreditLineFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getCreditLineFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryCreditLine, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getAllCreditLines(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-credit-lines",
+		Short: "Get all the open credit lines",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getAllCreditLinesFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getAllCreditLinesFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllCreditLines)
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getBorrowerCreditLines(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-borrower-credit-lines [user-address]",
+		Short: "Get all the open credit lines where address is borrower",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getBorrowerCreditLinesFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getBorrowerCreditLinesFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryBorrowerCreditLines, args[0])
 	res, _, err := cliCtx.QueryWithData(route, nil)
 
 	if err != nil {
@@ -103,4 +538,285 @@ func getAllDebtsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error
 	fmt.Println(string(res))
 
 	return nil
-}
\ No newline at end of file
+}
+
+func getLenderCreditLines(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-lender-credit-lines [user-address]",
+		Short: "Get all the open credit lines where address is lender",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getLenderCreditLinesFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getLenderCreditLinesFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryLenderCreditLines, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getPositions(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-positions [user-address]",
+		Short: "Get the debts and the credit lines of address, both as borrower and as lender",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getPositionsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getPositionsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryPositions, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getPool(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-pool [denom]",
+		Short: "Get the pool of the given denom, with its liquidity, utilization and share value",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getPoolFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getPoolFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryPool, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getAllPools(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-pools",
+		Short: "Get all the pools, with their liquidity, utilization and share value",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getAllPoolsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getAllPoolsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllPools)
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getPoolDeposits(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-pool-deposits [user-address]",
+		Short: "Get the deposits of address in the pools, and how much of them can be withdrawn",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getPoolDepositsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getPoolDepositsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryPoolDeposits, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getPoolRates(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-pool-rates [denom]",
+		Short: "Get the current borrow and supply APY of the pool of the given denom",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getPoolRatesFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getPoolRatesFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryPoolRates, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func calculateRates(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "calculate-rates [utilization]",
+		Short: "Calculate the borrow and supply APY of a rate model at the given utilization, such as 0.75",
+		Long: "Calculate the borrow and supply APY of a rate model at the given utilization, such as 0.75, " +
+			"without querying the chain. Rates and slopes are annual",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return calculateRatesFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd.Flags().String(flagModel, types.RateModelJumpRate, "the rate model (linear|jump)")
+	cmd.Flags().String(flagBaseRate, "0", "the annual rate at no utilization")
+	cmd.Flags().String(flagSlope, "0", "how much the annual rate grows from no to full utilization")
+	cmd.Flags().String(flagOptimalUtilization, "1", "the utilization beyond which the jump slope applies")
+	cmd.Flags().String(flagJumpSlope, "0", "how much the annual rate grows beyond the optimal utilization, for jump rate models")
+	cmd.Flags().String(flagInterestMethod, types.InterestCompound, "interest method of the loans (simple|compound)")
+	cmd.Flags().Int64(flagInterestPeriod, 1, "length of the interest accrual period of the loans")
+	cmd.Flags().String(flagInterestPeriodUnit, types.PeriodBlocks, "unit of the interest accrual period of the loans (blocks|seconds)")
+	cmd.Flags().Int64(flagBlocksPerYear, types.DefaultBlocksPerYear, "the expected number of blocks in a year")
+
+	return cmd
+}
+
+func calculateRatesFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	utilization, err := sdk.NewDecFromStr(args[0])
+	if err != nil {
+		return err
+	}
+	if utilization.IsNegative() || utilization.GT(sdk.OneDec()) {
+		return fmt.Errorf("utilization must be in [0, 1]: %s", utilization)
+	}
+
+	var rates [4]sdk.Dec
+	for i, flag := range []string{flagBaseRate, flagSlope, flagOptimalUtilization, flagJumpSlope} {
+		if rates[i], err = sdk.NewDecFromStr(viper.GetString(flag)); err != nil {
+			return fmt.Errorf("invalid %s: %s", flag, err)
+		}
+	}
+
+	model := types.NewJumpRateModel("", rates[0], rates[1], rates[2], rates[3])
+	if viper.GetString(flagModel) == types.RateModelLinear {
+		model = types.NewLinearRateModel("", rates[0], rates[1])
+	}
+	model.Kind = viper.GetString(flagModel)
+	if err := model.ValidateCurve(); err != nil {
+		return err
+	}
+
+	terms := types.NewInterestTerms(sdk.ZeroDec(), viper.GetString(flagInterestMethod),
+		viper.GetInt64(flagInterestPeriod), viper.GetString(flagInterestPeriodUnit))
+	if err := types.NewInterestTerms(sdk.OneDec(), terms.Method, terms.Period, terms.PeriodUnit).Validate(); err != nil {
+		return err
+	}
+
+	blocksPerYear := viper.GetInt64(flagBlocksPerYear)
+	if blocksPerYear <= 0 {
+		return fmt.Errorf("blocks per year must be positive: %d", blocksPerYear)
+	}
+
+	result := types.CalculateRates(model, terms, utilization, blocksPerYear)
+
+	bz, err := codec.MarshalJSONIndent(cdc, result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(bz))
+
+	return nil
+}
+
+func getPrice(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-price [denom]",
+		Short: "Get the current price of a denom, as aggregated from the oracles",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getPriceFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getPriceFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryPrice, args[0])
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
+
+func getParams(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "params",
+		Short: "Get the current lending parameters",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getParamsFunc(cmd, args, cdc)
+		},
+	}
+}
+
+func getParamsFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+	route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParams)
+	res, _, err := cliCtx.QueryWithData(route, nil)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(res))
+
+	return nil
+}
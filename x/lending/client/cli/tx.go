@@ -3,6 +3,8 @@ package cli
 import (
 	"bufio"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spoto/lending/x/lending/types"
 	"github.com/cosmos/cosmos-sdk/client"
@@ -13,6 +15,28 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagExpiry             = "expiry"
+	flagInterestRate       = "interest-rate"
+	flagInterestMethod     = "interest-method"
+	flagInterestPeriod     = "interest-period"
+	flagInterestPeriodUnit = "interest-period-unit"
+	flagMaturity           = "maturity"
+	flagGracePeriod        = "grace-period"
+	flagCollateral         = "collateral"
+	flagSchedule           = "schedule"
+	flagInstallments       = "installments"
+	flagInterval           = "installment-interval"
+	flagInstallmentDue     = "installment-due"
+	flagLateFee            = "late-fee"
+	flagLateFeeRate        = "late-fee-rate"
+	flagPenaltyRate        = "penalty-rate"
+	flagFull               = "full"
+	flagEscrow             = "escrow"
+	flagExpiryTime         = "expiry-time"
 )
 
 func GetTxCmd(cdc *codec.Codec) *cobra.Command {
@@ -28,6 +52,23 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 		createDebtCmd(cdc),
 		payDebtCmd(cdc),
 		changeDebtCmd(cdc),
+		proposeDebtCmd(cdc),
+		acceptDebtCmd(cdc),
+		rejectDebtCmd(cdc),
+		withdrawProposalCmd(cdc),
+		claimCollateralCmd(cdc),
+		postPriceCmd(cdc),
+		liquidateCmd(cdc),
+		transferDebtCmd(cdc),
+		consentTransferCmd(cdc),
+		openCreditLineCmd(cdc),
+		drawCreditCmd(cdc),
+		repayCreditCmd(cdc),
+		closeCreditLineCmd(cdc),
+		createPoolCmd(cdc),
+		depositToPoolCmd(cdc),
+		withdrawFromPoolCmd(cdc),
+		borrowFromPoolCmd(cdc),
 	)
 
 	return txCmd
@@ -36,7 +77,8 @@ func GetTxCmd(cdc *codec.Codec) *cobra.Command {
 func changeDebtCmd(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "change [ID] [amount]",
-		Short: "Change an amount (less than the original) for the debt",
+		Short: "Change an amount (up to what is owed) for the debt",
+		Long:  "Change an amount (up to what is owed) for the debt. The amount forgives the late fees first, then the accrued interest and then the principal, and can span many denoms of the debt, as in 10foo,5bar",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return changeDebtCmdFunc(cmd, args, cdc)
@@ -55,12 +97,12 @@ func changeDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) erro
 
 	ID := args[0]
 	creditor := cliCtx.GetFromAddress()
-	amount, err := sdk.ParseCoin(args[1])
+	amount, err := sdk.ParseCoins(args[1])
 	if err != nil {
 		return err
 	}
 
-	msg := types.NewMsgChangeDebt(ID, amount, creditor)
+	msg := types.NewMsgChangeDebtCoins(ID, amount, creditor)
 
 	if err := msg.ValidateBasic(); err != nil {
 		return err
@@ -73,12 +115,14 @@ func payDebtCmd(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "pay [ID] [amount]",
 		Short: "Pay an amount for the debt",
-		Args:  cobra.ExactArgs(2),
+		Long:  "Pay an amount for the debt, never more than what is owed, in any of its denoms, as in 10foo,5bar. Omit the amount and use --full to pay exactly what is owed",
+		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return payDebtCmdFunc(cmd, args, cdc)
 		},
 	}
 
+	cmd.Flags().Bool(flagFull, false, "pay the whole balance of the debt, including accrued interest")
 	cmd = flags.PostCommands(cmd)[0]
 
 	return cmd
@@ -91,12 +135,22 @@ func payDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
 
 	ID := args[0]
 	debtor := cliCtx.GetFromAddress()
-	amount, err := sdk.ParseCoin(args[1])
-	if err != nil {
-		return err
-	}
 
-	msg := types.NewMsgPayDebt(ID, amount, debtor)
+	var msg types.MsgPayDebt
+	switch full := viper.GetBool(flagFull); {
+	case full && len(args) == 2:
+		return fmt.Errorf("the amount cannot be given with --%s", flagFull)
+	case full:
+		msg = types.NewMsgPayDebtInFull(ID, debtor)
+	case len(args) == 1:
+		return fmt.Errorf("the amount is required, unless paying with --%s", flagFull)
+	default:
+		amount, err := sdk.ParseCoins(args[1])
+		if err != nil {
+			return err
+		}
+		msg = types.NewMsgPayDebtCoins(ID, amount, debtor)
+	}
 
 	if err := msg.ValidateBasic(); err != nil {
 		return err
@@ -107,14 +161,16 @@ func payDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
 
 func createDebtCmd(cdc *codec.Codec) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "create [ID] [amount] [creditor]",
-		Short: "Creates a debt that should be collected",
-		Args:  cobra.ExactArgs(3),
+		Use:   "create [ID] [amount] [debtor]",
+		Short: "Creates a debt that should be collected, signed by both creditor and debtor",
+		Long:  "Creates a debt that should be collected, signed by both creditor and debtor. Omit the ID to have one assigned by the chain",
+		Args:  cobra.RangeArgs(2, 3),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return createDebtCmdFunc(cmd, args, cdc)
 		},
 	}
 
+	addTermsFlags(cmd)
 	cmd = flags.PostCommands(cmd)[0]
 
 	return cmd
@@ -125,26 +181,741 @@ func createDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) erro
 	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
 	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
 
-	ID := args[0]
+	ID, args := splitOptionalID(args, 3)
 	creditor := cliCtx.GetFromAddress()
+	amount, err := sdk.ParseCoins(args[0])
+	if err != nil {
+		return err
+	}
+	debtor, err := sdk.AccAddressFromBech32(args[1])
+	if err != nil {
+		return err
+	}
+
+	debt := types.NewMultiCoinDebt(ID, debtor, amount, creditor)
+	if err := setTermsFromFlags(&debt); err != nil {
+		return err
+	}
+
+	msg := types.MsgCreateDebt(debt)
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func proposeDebtCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose [ID] [amount] [debtor]",
+		Short: "Proposes a debt that becomes active once the debtor accepts it",
+		Long:  "Proposes a debt that becomes active once the debtor accepts it. Omit the ID to have one assigned by the chain",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return proposeDebtCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd.Flags().Int64(flagExpiry, types.DefaultProposalExpiry, "number of blocks before the proposal expires")
+	addTermsFlags(cmd)
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func proposeDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	ID, args := splitOptionalID(args, 3)
+	creditor := cliCtx.GetFromAddress()
+	amount, err := sdk.ParseCoins(args[0])
+	if err != nil {
+		return err
+	}
+	debtor, err := sdk.AccAddressFromBech32(args[1])
+	if err != nil {
+		return err
+	}
+
+	debt := types.NewMultiCoinDebt(ID, debtor, amount, creditor)
+	if err := setTermsFromFlags(&debt); err != nil {
+		return err
+	}
+
+	msg := types.NewMsgProposeDebt(debt, viper.GetInt64(flagExpiry))
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func acceptDebtCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accept [ID]",
+		Short: "Accepts a debt proposed to you",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return acceptDebtCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func acceptDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgAcceptDebt(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func rejectDebtCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reject [ID]",
+		Short: "Rejects a debt proposed to you",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rejectDebtCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func rejectDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgRejectDebt(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func withdrawProposalCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw [ID]",
+		Short: "Withdraws a debt you proposed and that is still pending",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withdrawProposalCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func withdrawProposalCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgWithdrawProposal(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+// splitOptionalID yields the leading ID argument, if given, and the remaining arguments.
+// An empty ID lets the keeper assign one
+func splitOptionalID(args []string, withID int) (string, []string) {
+	if len(args) < withID {
+		return "", args
+	}
+
+	return args[0], args[1:]
+}
+
+// addTermsFlags adds the flags for the terms of a debt: its interest, its deadlines, its collateral,
+// its late fees and its repayment schedule
+func addTermsFlags(cmd *cobra.Command) {
+	addInterestFlags(cmd)
+	cmd.Flags().String(flagMaturity, "", "time when the debt is due, in RFC3339 format")
+	cmd.Flags().Duration(flagGracePeriod, 0, "how long after its maturity the debt defaults, such as 72h")
+	cmd.Flags().String(flagCollateral, "", "coins of the debtor locked until the debt is closed, such as 100foo,20bar")
+	cmd.Flags().String(flagLateFee, "", "flat fee charged for each missed due date, such as 10foo")
+	cmd.Flags().String(flagLateFeeRate, "0", "share of the amount overdue charged for each missed due date, such as 0.05")
+	cmd.Flags().String(flagPenaltyRate, "0", "interest rate applied at each period in place of the normal one while the debt is late")
+	cmd.Flags().String(flagSchedule, "", "repayment schedule, if the debt is repaid in installments (equal|balloon|custom)")
+	cmd.Flags().Int64(flagInstallments, 0, "number of installments of an equal or balloon schedule")
+	cmd.Flags().Duration(flagInterval, 0, "time between installments of an equal or balloon schedule, such as 720h")
+	cmd.Flags().StringArray(flagInstallmentDue, nil,
+		"due time and amount of an installment of a custom schedule, such as 2021-01-31T00:00:00Z=100foo; repeat for each installment")
+}
+
+// addInterestFlags adds the flags for how interest accrues
+func addInterestFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagInterestRate, "0", "interest rate applied at each period, such as 0.01")
+	cmd.Flags().String(flagInterestMethod, types.InterestSimple, "interest method (simple|compound)")
+	cmd.Flags().Int64(flagInterestPeriod, 0, "length of the interest accrual period")
+	cmd.Flags().String(flagInterestPeriodUnit, types.PeriodBlocks, "unit of the interest accrual period (blocks|seconds)")
+}
+
+func interestFromFlags() (types.InterestTerms, error) {
+	rate, err := sdk.NewDecFromStr(viper.GetString(flagInterestRate))
+	if err != nil {
+		return types.InterestTerms{}, err
+	}
+
+	return types.NewInterestTerms(
+		rate,
+		viper.GetString(flagInterestMethod),
+		viper.GetInt64(flagInterestPeriod),
+		viper.GetString(flagInterestPeriodUnit),
+	), nil
+}
+
+func setTermsFromFlags(debt *types.Debt) error {
+	var err error
+	if debt.Interest, err = interestFromFlags(); err != nil {
+		return err
+	}
+
+	if maturity := viper.GetString(flagMaturity); maturity != "" {
+		if debt.MaturityTime, err = time.Parse(time.RFC3339, maturity); err != nil {
+			return err
+		}
+	}
+
+	debt.GracePeriod = viper.GetDuration(flagGracePeriod)
+
+	if debt.Collateral, err = sdk.ParseCoins(viper.GetString(flagCollateral)); err != nil {
+		return err
+	}
+
+	lateFee, err := sdk.ParseCoins(viper.GetString(flagLateFee))
+	if err != nil {
+		return err
+	}
+	lateFeeRate, err := sdk.NewDecFromStr(viper.GetString(flagLateFeeRate))
+	if err != nil {
+		return err
+	}
+	penaltyRate, err := sdk.NewDecFromStr(viper.GetString(flagPenaltyRate))
+	if err != nil {
+		return err
+	}
+	debt.LateFees = types.NewLateFeeTerms(lateFee, lateFeeRate, penaltyRate)
+
+	scheduleType := types.ScheduleType(viper.GetString(flagSchedule))
+	if scheduleType != types.ScheduleCustom {
+		debt.Repayment = types.NewScheduleTerms(scheduleType, viper.GetInt64(flagInstallments), viper.GetDuration(flagInterval))
+		return nil
+	}
+
+	var installments []types.InstallmentDue
+	for _, due := range viper.GetStringSlice(flagInstallmentDue) {
+		parts := strings.SplitN(due, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("installments must be given as due-time=amount, not %s", due)
+		}
+
+		dueTime, err := time.Parse(time.RFC3339, parts[0])
+		if err != nil {
+			return err
+		}
+		amount, err := sdk.ParseCoins(parts[1])
+		if err != nil {
+			return err
+		}
+
+		installments = append(installments, types.NewInstallmentDue(dueTime, amount))
+	}
+	debt.Repayment = types.NewCustomScheduleTerms(installments...)
+
+	return nil
+}
+
+func claimCollateralCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claim-collateral [ID]",
+		Short: "Claims the collateral of a defaulted debt you are creditor of",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return claimCollateralCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func claimCollateralCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgClaimCollateral(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func postPriceCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "post-price [denom] [price]",
+		Short: "Reports the price of a denom, if you are a whitelisted oracle",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postPriceCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func postPriceCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	price, err := sdk.NewDecFromStr(args[1])
+	if err != nil {
+		return err
+	}
+
+	msg := types.NewMsgPostPrice(cliCtx.GetFromAddress(), args[0], price)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func liquidateCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "liquidate [ID]",
+		Short: "Pays an under-collateralized debt in exchange for its collateral at a discount",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return liquidateCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func liquidateCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgLiquidate(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func transferDebtCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer [ID] [new-creditor]",
+		Short: "Assigns a debt you are creditor of to a new creditor",
+		Long: "Assigns a debt you are creditor of to a new creditor. If the module params require it, " +
+			"the transfer takes place only once the debtor consents to it",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return transferDebtCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func transferDebtCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	newCreditor, err := sdk.AccAddressFromBech32(args[1])
+	if err != nil {
+		return err
+	}
+
+	msg := types.NewMsgTransferDebt(args[0], cliCtx.GetFromAddress(), newCreditor)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func consentTransferCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "consent-transfer [ID]",
+		Short: "Consents to the pending transfer of a debt you owe to a new creditor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return consentTransferCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func consentTransferCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgConsentTransfer(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func openCreditLineCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open-credit-line [ID] [limit] [borrower]",
+		Short: "Grants a credit line that the borrower can draw from up to the limit",
+		Long: "Grants a credit line that the borrower can draw from up to the limit, repay and draw again. " +
+			"With --escrow, the whole limit moves at once to the module account, otherwise each draw comes from you. " +
+			"Omit the ID to have one assigned by the chain",
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return openCreditLineCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	addInterestFlags(cmd)
+	cmd.Flags().String(flagExpiryTime, "", "time after which the borrower cannot draw anymore, in RFC3339 format")
+	cmd.Flags().Bool(flagEscrow, false, "fund the whole limit in advance, held by the module account")
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func openCreditLineCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	ID, args := splitOptionalID(args, 3)
+	lender := cliCtx.GetFromAddress()
+	limit, err := sdk.ParseCoin(args[0])
+	if err != nil {
+		return err
+	}
+	borrower, err := sdk.AccAddressFromBech32(args[1])
+	if err != nil {
+		return err
+	}
+
+	line := types.NewCreditLine(ID, lender, borrower, limit)
+	if line.Interest, err = interestFromFlags(); err != nil {
+		return err
+	}
+	if expiry := viper.GetString(flagExpiryTime); expiry != "" {
+		if line.ExpiryTime, err = time.Parse(time.RFC3339, expiry); err != nil {
+			return err
+		}
+	}
+	line.Escrowed = viper.GetBool(flagEscrow)
+
+	msg := types.NewMsgOpenCreditLine(line)
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func drawCreditCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "draw-credit [ID] [amount]",
+		Short: "Draws an amount from a credit line granted to you, never beyond its limit",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return drawCreditCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func drawCreditCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
 	amount, err := sdk.ParseCoin(args[1])
 	if err != nil {
 		return err
 	}
-	debtor, err := sdk.AccAddressFromBech32(args[2])
+
+	msg := types.NewMsgDrawCredit(args[0], amount, cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func repayCreditCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repay-credit [ID] [amount]",
+		Short: "Repays an amount of a credit line granted to you",
+		Long:  "Repays an amount of a credit line granted to you, never more than what is owed. Omit the amount and use --full to repay exactly what is owed",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return repayCreditCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd.Flags().Bool(flagFull, false, "repay what is drawn from the credit line, including accrued interest")
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func repayCreditCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	ID := args[0]
+	borrower := cliCtx.GetFromAddress()
+
+	var msg types.MsgRepayCredit
+	switch full := viper.GetBool(flagFull); {
+	case full && len(args) == 2:
+		return fmt.Errorf("the amount cannot be given with --%s", flagFull)
+	case full:
+		msg = types.NewMsgRepayCreditInFull(ID, borrower)
+	case len(args) == 1:
+		return fmt.Errorf("the amount is required, unless repaying with --%s", flagFull)
+	default:
+		amount, err := sdk.ParseCoin(args[1])
+		if err != nil {
+			return err
+		}
+		msg = types.NewMsgRepayCredit(ID, amount, borrower)
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func closeCreditLineCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "close-credit-line [ID]",
+		Short: "Closes a credit line you are a party of, once nothing is owed on it",
+		Long:  "Closes a credit line you are a party of, once nothing is owed on it. The escrow of the line, if any, goes back to the lender",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return closeCreditLineCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func closeCreditLineCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	msg := types.NewMsgCloseCreditLine(args[0], cliCtx.GetFromAddress())
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func createPoolCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-pool [denom]",
+		Short: "Creates the pool of a denom, that many lenders can deposit into and borrowers can draw loans from. Only the pool creators of the params can",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return createPoolCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	addInterestFlags(cmd)
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func createPoolCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	interest, err := interestFromFlags()
+	if err != nil {
+		return err
+	}
+
+	msg := types.NewMsgCreatePool(cliCtx.GetFromAddress(), args[0], interest)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func depositToPoolCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deposit-to-pool [amount]",
+		Short: "Deposits an amount into the pool of its denom, in exchange for share tokens of the pool",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return depositToPoolCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func depositToPoolCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	amount, err := sdk.ParseCoin(args[0])
+	if err != nil {
+		return err
+	}
+
+	msg := types.NewMsgDepositToPool(cliCtx.GetFromAddress(), amount)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func withdrawFromPoolCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "withdraw-from-pool [denom] [shares]",
+		Short: "Burns share tokens of the pool of a denom in exchange for what they are worth",
+		Long:  "Burns share tokens of the pool of a denom in exchange for what they are worth, as long as the pool holds enough liquidity",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withdrawFromPoolCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func withdrawFromPoolCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	shares, ok := sdk.NewIntFromString(args[1])
+	if !ok {
+		return fmt.Errorf("invalid shares %s", args[1])
+	}
+
+	msg := types.NewMsgWithdrawFromPool(cliCtx.GetFromAddress(), args[0], shares)
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
+}
+
+func borrowFromPoolCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "borrow-from-pool [ID] [amount] [collateral]",
+		Short: "Draws a collateralized loan from the pool of the denom of the amount",
+		Long: "Draws a collateralized loan from the pool of the denom of the amount, as a debt with the pool as creditor. " +
+			"The collateral, such as 100bar,20baz, must be worth at least the liquidation ratio of the loan. " +
+			"Omit the ID to have one assigned by the chain",
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return borrowFromPoolCmdFunc(cmd, args, cdc)
+		},
+	}
+
+	cmd = flags.PostCommands(cmd)[0]
+
+	return cmd
+}
+
+func borrowFromPoolCmdFunc(cmd *cobra.Command, args []string, cdc *codec.Codec) error {
+	inBuf := bufio.NewReader(cmd.InOrStdin())
+	cliCtx := context.NewCLIContextWithInput(inBuf).WithCodec(cdc)
+	txBldr := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+
+	ID, args := splitOptionalID(args, 3)
+	amount, err := sdk.ParseCoin(args[0])
 	if err != nil {
 		return err
 	}
+	collateral, err := sdk.ParseCoins(args[1])
+	if err != nil {
+		return err
+	}
+
+	msg := types.NewMsgBorrowFromPool(ID, cliCtx.GetFromAddress(), amount, collateral)
 
-	msg := types.MsgCreateDebt(types.Debt{
-		ID:       ID,
-		Debtor:   debtor,
-		Amount:   amount,
-		Creditor: creditor,
-	})
 	if err := msg.ValidateBasic(); err != nil {
 		return err
 	}
 
 	return utils.CompleteAndBroadcastTxCLI(txBldr, cliCtx, []sdk.Msg{msg})
-}
\ No newline at end of file
+}
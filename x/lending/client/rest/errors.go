@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/spoto/lending/x/lending/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+// abciError is an error reported by the node: its message is the log of the
+// response, while it unwraps to the registered error with the same code
+type abciError struct {
+	registered error
+	log        string
+}
+
+func (err abciError) Error() string { return err.log }
+func (err abciError) Unwrap() error { return err.registered }
+
+// queryWithData performs a query like the CLI context does, but keeps the
+// codespace and code of a failed query, so that its HTTP status can be chosen
+func queryWithData(cliCtx context.CLIContext, path string, data []byte) ([]byte, int64, error) {
+	node, err := cliCtx.GetNode()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := rpcclient.ABCIQueryOptions{
+		Height: cliCtx.Height,
+		Prove:  !cliCtx.TrustNode,
+	}
+
+	result, err := node.ABCIQueryWithOptions(path, data, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp := result.Response
+	if !resp.IsOK() {
+		return nil, resp.Height, abciError{sdkErr.ABCIError(resp.Codespace, resp.Code, resp.Log), resp.Log}
+	}
+
+	return resp.Value, resp.Height, nil
+}
+
+// httpStatus yields the HTTP status code that corresponds to the error
+func httpStatus(err error) int {
+	switch {
+	case isOf(err, types.ErrDebtNotFound, types.ErrProposalNotFound, types.ErrPriceNotFound,
+		types.ErrTransferNotFound, types.ErrCreditLineNotFound, types.ErrPoolNotFound):
+		return http.StatusNotFound
+	case isOf(err, types.ErrUnauthorized, types.ErrNotOracle, types.ErrNotPoolCreator, sdkErr.ErrUnauthorized):
+		return http.StatusForbidden
+	case isOf(err, types.ErrDuplicateID, types.ErrDebtClosed, types.ErrInvalidDebtStatus, types.ErrProposalExpired,
+		types.ErrNotLiquidatable, types.ErrNoCollateral, types.ErrCreditLineExpired, types.ErrCreditLineInUse,
+		types.ErrPoolExists):
+		return http.StatusConflict
+	case isOf(err, sdkErr.ErrInsufficientFunds, types.ErrCreditLimitExceeded, types.ErrInsufficientLiquidity,
+		types.ErrUndercollateralized):
+		return http.StatusUnprocessableEntity
+	case isOf(err, types.ErrInvalidAmount, types.ErrDenomNotAllowed, types.ErrAmountTooLarge,
+		types.ErrInterestRateTooHigh, types.ErrLateFeeTooHigh, types.ErrExpiryTooShort, types.ErrTooManyInstallments,
+		types.ErrDenomMismatch, sdkErr.ErrInvalidRequest, sdkErr.ErrInvalidAddress,
+		sdkErr.ErrInvalidCoins, sdkErr.ErrJSONUnmarshal, sdkErr.ErrUnknownRequest):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func isOf(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeErrorResponse answers with the error, under its HTTP status code
+func writeErrorResponse(w http.ResponseWriter, err error) {
+	rest.WriteErrorResponse(w, httpStatus(err), err.Error())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
@@ -13,6 +14,10 @@ import (
 )
 
 func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{id}", types.ModuleName, types.QueryDebt),
+		queryDebtFn(cliCtx),
+	).Methods("GET")
 	route := fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryAllDebts)
 	r.HandleFunc(route, queryDebtsFn(cliCtx)).Methods("GET")
 	r.HandleFunc(
@@ -23,6 +28,168 @@ func registerQueryRoutes(cliCtx context.CLIContext, r *mux.Router) {
 		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryCreditorDebts),
 		queryCreditorDebtsFn(cliCtx),
 	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryDebtHistory),
+		queryDebtHistoryFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryDebtHistory),
+		queryDebtHistoryFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{id}", types.ModuleName, types.QueryDebtLedger),
+		queryDebtLedgerFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{id}", types.ModuleName, types.QueryDebtSchedule),
+		queryDebtScheduleFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryAllProposals),
+		queryProposalsFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryDebtorProposals),
+		queryByAddressFn(cliCtx, types.QueryDebtorProposals),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryCreditorProposals),
+		queryByAddressFn(cliCtx, types.QueryCreditorProposals),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryTransfers),
+		queryTransfersFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{id}", types.ModuleName, types.QueryCreditLine),
+		queryCreditLineFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryAllCreditLines),
+		queryCreditLinesFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryBorrowerCreditLines),
+		queryByAddressFn(cliCtx, types.QueryBorrowerCreditLines),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryLenderCreditLines),
+		queryByAddressFn(cliCtx, types.QueryLenderCreditLines),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryPositions),
+		queryByAddressFn(cliCtx, types.QueryPositions),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{denom}", types.ModuleName, types.QueryPool),
+		queryByDenomFn(cliCtx, types.QueryPool),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{denom}", types.ModuleName, types.QueryPoolRates),
+		queryByDenomFn(cliCtx, types.QueryPoolRates),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryAllPools),
+		queryPoolsFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{address}", types.ModuleName, types.QueryPoolDeposits),
+		queryByAddressFn(cliCtx, types.QueryPoolDeposits),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s/{denom}", types.ModuleName, types.QueryPrice),
+		queryPriceFn(cliCtx),
+	).Methods("GET")
+	r.HandleFunc(
+		fmt.Sprintf("/%s/%s", types.ModuleName, types.QueryParams),
+		queryParamsFn(cliCtx),
+	).Methods("GET")
+}
+
+// queryDebtsParams yields the JSON of the query params in the URL of the request:
+// page, limit, status, denom, min_amount, max_amount and counterparty
+func queryDebtsParams(cliCtx context.CLIContext, r *http.Request) ([]byte, error) {
+	params := types.NewQueryDebtsParams(1, types.DefaultQueryLimit)
+
+	if page := r.FormValue("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil {
+			return nil, err
+		}
+		params.Page = value
+	}
+
+	if limit := r.FormValue("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, err
+		}
+		params.Limit = value
+	}
+
+	if status := r.FormValue("status"); status != "" {
+		debtStatus, err := types.DebtStatusFromString(status)
+		if err != nil {
+			return nil, err
+		}
+		params.Status = debtStatus
+	}
+
+	params.Denom = r.FormValue("denom")
+
+	if minAmount := r.FormValue("min_amount"); minAmount != "" {
+		amount, ok := sdk.NewIntFromString(minAmount)
+		if !ok {
+			return nil, fmt.Errorf("invalid min amount %s", minAmount)
+		}
+		params.MinAmount = amount
+	}
+
+	if maxAmount := r.FormValue("max_amount"); maxAmount != "" {
+		amount, ok := sdk.NewIntFromString(maxAmount)
+		if !ok {
+			return nil, fmt.Errorf("invalid max amount %s", maxAmount)
+		}
+		params.MaxAmount = amount
+	}
+
+	if counterparty := r.FormValue("counterparty"); counterparty != "" {
+		address, err := sdk.AccAddressFromBech32(counterparty)
+		if err != nil {
+			return nil, err
+		}
+		params.Counterparty = address
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cliCtx.Codec.MarshalJSON(params)
+}
+
+// queryDebtFn serves the debt with the ID in the path, or answers
+// with a not found error if there is no such debt
+func queryDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebt, id)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
 }
 
 func queryDebtsFn(cliCtx context.CLIContext) http.HandlerFunc {
@@ -32,11 +199,17 @@ func queryDebtsFn(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
+		bz, err := queryDebtsParams(cliCtx, r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllDebts)
 
-		res, height, err := cliCtx.QueryWithData(route, nil)
+		res, height, err := queryWithData(cliCtx, route, bz)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			writeErrorResponse(w, err)
 			return
 		}
 
@@ -52,7 +225,7 @@ func queryDebtorDebtsFn(cliCtx context.CLIContext) http.HandlerFunc {
 
 		addr, err := sdk.AccAddressFromBech32(bech32addr)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
@@ -61,11 +234,17 @@ func queryDebtorDebtsFn(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
+		bz, err := queryDebtsParams(cliCtx, r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtorDebts, addr)
 
-		res, height, err := cliCtx.QueryWithData(route, nil)
+		res, height, err := queryWithData(cliCtx, route, bz)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			writeErrorResponse(w, err)
 			return
 		}
 
@@ -81,7 +260,7 @@ func queryCreditorDebtsFn(cliCtx context.CLIContext) http.HandlerFunc {
 
 		addr, err := sdk.AccAddressFromBech32(bech32addr)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
 
@@ -90,15 +269,310 @@ func queryCreditorDebtsFn(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
+		bz, err := queryDebtsParams(cliCtx, r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryCreditorDebts, addr)
 
-		res, height, err := cliCtx.QueryWithData(route, nil)
+		res, height, err := queryWithData(cliCtx, route, bz)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			writeErrorResponse(w, err)
 			return
 		}
 
 		cliCtx = cliCtx.WithHeight(height)
 		rest.PostProcessResponse(w, cliCtx, res)
 	}
-}
\ No newline at end of file
+}
+
+// queryDebtHistoryFn serves the archived debts, all of them
+// or only those of the address in the path, if any
+func queryDebtHistoryFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryDebtHistory)
+
+		if bech32addr, ok := mux.Vars(r)["address"]; ok {
+			addr, err := sdk.AccAddressFromBech32(bech32addr)
+			if err != nil {
+				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			route = fmt.Sprintf("%s/%s", route, addr)
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		bz, err := queryDebtsParams(cliCtx, r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res, height, err := queryWithData(cliCtx, route, bz)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// queryDebtLedgerFn serves a page of the ledger of the debt with the ID in the path,
+// or answers with a not found error if there is no such debt, active or archived
+func queryDebtLedgerFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		bz, err := queryDebtsParams(cliCtx, r)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtLedger, id)
+
+		res, height, err := queryWithData(cliCtx, route, bz)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// queryDebtScheduleFn serves the installments of the debt with the ID in the path,
+// or answers with a not found error if there is no such debt, active or archived
+func queryDebtScheduleFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryDebtSchedule, id)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryProposalsFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllProposals)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// queryByAddressFn serves the proposals, the credit lines or the pool deposits of an address,
+// according to the given query endpoint
+func queryByAddressFn(cliCtx context.CLIContext, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		bech32addr := vars["address"]
+
+		addr, err := sdk.AccAddressFromBech32(bech32addr)
+		if err != nil {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, endpoint, addr)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryTransfersFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryTransfers)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryCreditLineFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryCreditLine, id)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryCreditLinesFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllCreditLines)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// queryByDenomFn serves the pool of a denom or its current rates, depending on the endpoint
+func queryByDenomFn(cliCtx context.CLIContext, endpoint string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		denom := mux.Vars(r)["denom"]
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, endpoint, denom)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryPoolsFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryAllPools)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryPriceFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		denom := mux.Vars(r)["denom"]
+
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s/%s", types.QuerierRoute, types.QueryPrice, denom)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func queryParamsFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(w, cliCtx, r)
+		if !ok {
+			return
+		}
+
+		route := fmt.Sprintf("custom/%s/%s", types.QuerierRoute, types.QueryParams)
+
+		res, height, err := queryWithData(cliCtx, route, nil)
+		if err != nil {
+			writeErrorResponse(w, err)
+			return
+		}
+
+		cliCtx = cliCtx.WithHeight(height)
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
@@ -9,51 +9,454 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/spoto/lending/x/lending/types"
 	"net/http"
+	"time"
 )
 
 func registerTxRoutes(cliCtx context.CLIContext, r *mux.Router) {
 	r.HandleFunc(fmt.Sprintf("/%s/createdebt", types.ModuleName), createDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/paydebt", types.ModuleName), payDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/changedebt", types.ModuleName), changeDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/proposedebt", types.ModuleName), proposeDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/acceptdebt", types.ModuleName), acceptDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/rejectdebt", types.ModuleName), rejectDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/withdrawproposal", types.ModuleName), withdrawProposalFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/claimcollateral", types.ModuleName), claimCollateralFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/postprice", types.ModuleName), postPriceFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/liquidate", types.ModuleName), liquidateFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/transferdebt", types.ModuleName), transferDebtFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/consenttransfer", types.ModuleName), consentTransferFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/opencreditline", types.ModuleName), openCreditLineFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/drawcredit", types.ModuleName), drawCreditFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/repaycredit", types.ModuleName), repayCreditFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/closecreditline", types.ModuleName), closeCreditLineFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/createpool", types.ModuleName), createPoolFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/deposittopool", types.ModuleName), depositToPoolFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/withdrawfrompool", types.ModuleName), withdrawFromPoolFn(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/borrowfrompool", types.ModuleName), borrowFromPoolFn(cliCtx)).Methods("POST")
 }
 
 type createDebtRequest struct {
-	BaseReq rest.BaseReq `json:"base_req"`
-	ID     string         `json:"ID"`
-	Debtor sdk.AccAddress `json:"debtor"`
-	Amount   sdk.Coin       `json:"amount"`
-	Creditor sdk.AccAddress `json:"creditor"`
+	BaseReq      rest.BaseReq        `json:"base_req"`
+	ID           string              `json:"ID"` // assigned by the chain if empty
+	Debtor       sdk.AccAddress      `json:"debtor"`
+	Amount       types.DebtCoins     `json:"amount"` // a coin, or a list of coins in many denoms
+	Creditor     sdk.AccAddress      `json:"creditor"`
+	Interest     types.InterestTerms `json:"interest"`
+	MaturityTime time.Time           `json:"maturity_time"`
+	GracePeriod  time.Duration       `json:"grace_period"`
+	Collateral   sdk.Coins           `json:"collateral"`
+	Repayment    types.ScheduleTerms `json:"repayment"`
+	LateFees     types.LateFeeTerms  `json:"late_fees"`
 }
 
 func createDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req createDebtRequest
 
-		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
 			return
 		}
 
-		baseReq := req.BaseReq.Sanitize()
+		// create the message
+		msg := types.MsgCreateDebt(req.debt())
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, msg)
+	}
+}
 
-		if !baseReq.ValidateBasic(w) {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, "invalid request")
+// debt yields the debt described by the request
+func (req createDebtRequest) debt() types.Debt {
+	debt := types.NewMultiCoinDebt(req.ID, req.Debtor, sdk.Coins(req.Amount), req.Creditor)
+	if !req.Interest.Rate.IsNil() {
+		debt.Interest = req.Interest
+	}
+	debt.MaturityTime = req.MaturityTime
+	debt.GracePeriod = req.GracePeriod
+	debt.Collateral = req.Collateral
+	debt.Repayment = req.Repayment
+	if !req.LateFees.IsZero() {
+		debt.LateFees = req.LateFees
+	}
+
+	return debt
+}
+
+// readTxRequest parses the body of the request into req and yields its sanitized
+// base request, or answers with a bad request error and yields false
+func readTxRequest(w http.ResponseWriter, r *http.Request, cliCtx context.CLIContext, req interface{},
+	baseReq func() rest.BaseReq) (rest.BaseReq, bool) {
+	if !rest.ReadRESTReq(w, r, cliCtx.Codec, req) {
+		rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+		return rest.BaseReq{}, false
+	}
+
+	sanitized := baseReq().Sanitize()
+	if !sanitized.ValidateBasic(w) {
+		rest.WriteErrorResponse(w, http.StatusBadRequest, "invalid request")
+		return rest.BaseReq{}, false
+	}
+
+	return sanitized, true
+}
+
+// writeGenerateStdTxResponse answers with the unsigned transaction of the
+// message, after the same validation that the chain performs on it
+func writeGenerateStdTxResponse(w http.ResponseWriter, cliCtx context.CLIContext, baseReq rest.BaseReq, msg sdk.Msg) {
+	if err := msg.ValidateBasic(); err != nil {
+		writeErrorResponse(w, err)
+		return
+	}
+
+	utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+}
+
+type proposeDebtRequest struct {
+	BaseReq      rest.BaseReq        `json:"base_req"`
+	ID           string              `json:"ID"` // assigned by the chain if empty
+	Debtor       sdk.AccAddress      `json:"debtor"`
+	Amount       types.DebtCoins     `json:"amount"` // a coin, or a list of coins in many denoms
+	Creditor     sdk.AccAddress      `json:"creditor"`
+	Interest     types.InterestTerms `json:"interest"`
+	MaturityTime time.Time           `json:"maturity_time"`
+	GracePeriod  time.Duration       `json:"grace_period"`
+	Collateral   sdk.Coins           `json:"collateral"`
+	Repayment    types.ScheduleTerms `json:"repayment"`
+	LateFees     types.LateFeeTerms  `json:"late_fees"`
+	Expiry       int64               `json:"expiry"` // blocks before the proposal expires, the default if 0
+}
+
+func proposeDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req proposeDebtRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
 			return
 		}
 
-		// create the message
-		msg := types.MsgCreateDebt {
-			ID:       req.ID,
-			Amount:   req.Amount,
-			Creditor: req.Creditor,
-			Debtor:   req.Debtor,
+		debt := createDebtRequest{
+			ID:           req.ID,
+			Debtor:       req.Debtor,
+			Amount:       req.Amount,
+			Creditor:     req.Creditor,
+			Interest:     req.Interest,
+			MaturityTime: req.MaturityTime,
+			GracePeriod:  req.GracePeriod,
+			Collateral:   req.Collateral,
+			Repayment:    req.Repayment,
+			LateFees:     req.LateFees,
+		}.debt()
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgProposeDebt(debt, req.Expiry))
+	}
+}
+
+type payDebtRequest struct {
+	BaseReq rest.BaseReq    `json:"base_req"`
+	ID      string          `json:"ID"`
+	Amount  types.DebtCoins `json:"amount"` // left out when paying in full
+	Debtor  sdk.AccAddress  `json:"debtor"`
+	InFull  bool            `json:"in_full"`
+}
+
+func payDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req payDebtRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgPayDebtCoins(req.ID, sdk.Coins(req.Amount), req.Debtor)
+		msg.InFull = req.InFull
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, msg)
+	}
+}
+
+type changeDebtRequest struct {
+	BaseReq  rest.BaseReq    `json:"base_req"`
+	ID       string          `json:"ID"`
+	Amount   types.DebtCoins `json:"amount"`
+	Creditor sdk.AccAddress  `json:"creditor"`
+}
+
+func changeDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req changeDebtRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
 		}
 
-		err := msg.ValidateBasic()
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgChangeDebtCoins(req.ID, sdk.Coins(req.Amount), req.Creditor))
+	}
+}
 
-		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+// idRequest is the body of the requests that only name a debt, proposal or credit line
+type idRequest struct {
+	BaseReq rest.BaseReq   `json:"base_req"`
+	ID      string         `json:"ID"`
+	Sender  sdk.AccAddress `json:"sender"` // the party that signs the message
+}
+
+// idRequestFn serves the requests whose message is built from an ID and its sender
+func idRequestFn(cliCtx context.CLIContext, newMsg func(id string, sender sdk.AccAddress) sdk.Msg) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req idRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
 			return
 		}
 
-		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, newMsg(req.ID, req.Sender))
 	}
-}
\ No newline at end of file
+}
+
+func acceptDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, debtor sdk.AccAddress) sdk.Msg {
+		return types.NewMsgAcceptDebt(id, debtor)
+	})
+}
+
+func rejectDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, debtor sdk.AccAddress) sdk.Msg {
+		return types.NewMsgRejectDebt(id, debtor)
+	})
+}
+
+func withdrawProposalFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, creditor sdk.AccAddress) sdk.Msg {
+		return types.NewMsgWithdrawProposal(id, creditor)
+	})
+}
+
+func claimCollateralFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, creditor sdk.AccAddress) sdk.Msg {
+		return types.NewMsgClaimCollateral(id, creditor)
+	})
+}
+
+func liquidateFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, liquidator sdk.AccAddress) sdk.Msg {
+		return types.NewMsgLiquidate(id, liquidator)
+	})
+}
+
+func consentTransferFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, debtor sdk.AccAddress) sdk.Msg {
+		return types.NewMsgConsentTransfer(id, debtor)
+	})
+}
+
+type transferDebtRequest struct {
+	BaseReq     rest.BaseReq   `json:"base_req"`
+	ID          string         `json:"ID"`
+	Creditor    sdk.AccAddress `json:"creditor"`
+	NewCreditor sdk.AccAddress `json:"new_creditor"`
+}
+
+func transferDebtFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req transferDebtRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgTransferDebt(req.ID, req.Creditor, req.NewCreditor))
+	}
+}
+
+type postPriceRequest struct {
+	BaseReq rest.BaseReq   `json:"base_req"`
+	Oracle  sdk.AccAddress `json:"oracle"`
+	Denom   string         `json:"denom"`
+	Price   sdk.Dec        `json:"price"`
+}
+
+func postPriceFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req postPriceRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgPostPrice(req.Oracle, req.Denom, req.Price))
+	}
+}
+
+type openCreditLineRequest struct {
+	BaseReq    rest.BaseReq        `json:"base_req"`
+	ID         string              `json:"ID"` // assigned by the chain if empty
+	Lender     sdk.AccAddress      `json:"lender"`
+	Borrower   sdk.AccAddress      `json:"borrower"`
+	Limit      sdk.Coin            `json:"limit"`
+	Interest   types.InterestTerms `json:"interest"`
+	ExpiryTime time.Time           `json:"expiry_time"`
+	Escrowed   bool                `json:"escrowed"`
+}
+
+func openCreditLineFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req openCreditLineRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		line := types.NewCreditLine(req.ID, req.Lender, req.Borrower, req.Limit)
+		if !req.Interest.Rate.IsNil() {
+			line.Interest = req.Interest
+		}
+		line.ExpiryTime = req.ExpiryTime
+		line.Escrowed = req.Escrowed
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgOpenCreditLine(line))
+	}
+}
+
+type drawCreditRequest struct {
+	BaseReq  rest.BaseReq   `json:"base_req"`
+	ID       string         `json:"ID"`
+	Amount   sdk.Coin       `json:"amount"`
+	Borrower sdk.AccAddress `json:"borrower"`
+}
+
+func drawCreditFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req drawCreditRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgDrawCredit(req.ID, req.Amount, req.Borrower))
+	}
+}
+
+type repayCreditRequest struct {
+	BaseReq  rest.BaseReq   `json:"base_req"`
+	ID       string         `json:"ID"`
+	Amount   sdk.Coin       `json:"amount"` // left out when repaying in full
+	Borrower sdk.AccAddress `json:"borrower"`
+	InFull   bool           `json:"in_full"`
+}
+
+func repayCreditFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req repayCreditRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		msg := types.NewMsgRepayCredit(req.ID, req.Amount, req.Borrower)
+		if req.InFull {
+			msg = types.NewMsgRepayCreditInFull(req.ID, req.Borrower)
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, msg)
+	}
+}
+
+func closeCreditLineFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return idRequestFn(cliCtx, func(id string, sender sdk.AccAddress) sdk.Msg {
+		return types.NewMsgCloseCreditLine(id, sender)
+	})
+}
+
+type createPoolRequest struct {
+	BaseReq  rest.BaseReq        `json:"base_req"`
+	Creator  sdk.AccAddress      `json:"creator"`
+	Denom    string              `json:"denom"`
+	Interest types.InterestTerms `json:"interest"`
+}
+
+func createPoolFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req createPoolRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		interest := req.Interest
+		if interest.Rate.IsNil() {
+			interest = types.NoInterest()
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgCreatePool(req.Creator, req.Denom, interest))
+	}
+}
+
+type depositToPoolRequest struct {
+	BaseReq   rest.BaseReq   `json:"base_req"`
+	Depositor sdk.AccAddress `json:"depositor"`
+	Amount    sdk.Coin       `json:"amount"`
+}
+
+func depositToPoolFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req depositToPoolRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgDepositToPool(req.Depositor, req.Amount))
+	}
+}
+
+type withdrawFromPoolRequest struct {
+	BaseReq   rest.BaseReq   `json:"base_req"`
+	Depositor sdk.AccAddress `json:"depositor"`
+	Denom     string         `json:"denom"`
+	Shares    sdk.Int        `json:"shares"`
+}
+
+func withdrawFromPoolFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req withdrawFromPoolRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgWithdrawFromPool(req.Depositor, req.Denom, req.Shares))
+	}
+}
+
+type borrowFromPoolRequest struct {
+	BaseReq    rest.BaseReq   `json:"base_req"`
+	ID         string         `json:"ID"` // assigned by the chain if empty
+	Borrower   sdk.AccAddress `json:"borrower"`
+	Amount     sdk.Coin       `json:"amount"`
+	Collateral sdk.Coins      `json:"collateral"`
+}
+
+func borrowFromPoolFn(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req borrowFromPoolRequest
+
+		baseReq, ok := readTxRequest(w, r, cliCtx, &req, func() rest.BaseReq { return req.BaseReq })
+		if !ok {
+			return
+		}
+
+		writeGenerateStdTxResponse(w, cliCtx, baseReq, types.NewMsgBorrowFromPool(req.ID, req.Borrower, req.Amount, req.Collateral))
+	}
+}
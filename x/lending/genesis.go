@@ -7,14 +7,74 @@ import (
 
 // InitGenesis initialize default parameters
 // and the keeper's address to pubkey map
-func InitGenesis(ctx sdk.Context, k Keeper /* TODO: Define what keepers the module needs */, data types.GenesisState) {
-	// TODO: Define logic for when you would like to initialize a new genesis
+func InitGenesis(ctx sdk.Context, k Keeper, data types.GenesisState) {
+	k.SetParams(ctx, data.Params)
+	k.SetNextDebtSequence(ctx, data.NextDebtSequence)
+
+	for _, debt := range data.Debts {
+		// closed debts exported before the archive existed are archived now
+		if debt.Status.IsClosed() {
+			k.SetArchivedDebt(ctx, types.NewArchivedDebt(debt, ctx.BlockHeight(), ctx.BlockTime()))
+			continue
+		}
+
+		if err := k.CreateDebt(ctx, debt); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, proposal := range data.Proposals {
+		if err := k.CreateProposal(ctx, proposal); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, price := range data.Prices {
+		k.SetPostedPrice(ctx, price)
+	}
+
+	for _, archived := range data.ArchivedDebts {
+		k.SetArchivedDebt(ctx, archived)
+	}
+
+	for _, entry := range data.LedgerEntries {
+		k.SetLedgerEntry(ctx, entry)
+	}
+
+	for _, transfer := range data.Transfers {
+		k.SetTransfer(ctx, transfer)
+	}
+
+	// the escrow of the credit lines is already held by the module account
+	for _, line := range data.CreditLines {
+		k.SetCreditLine(ctx, line)
+	}
+
+	// the liquidity of the pools is held by their accounts, and their shares by the lenders
+	for _, pool := range data.Pools {
+		k.SetPool(ctx, pool)
+	}
+
+	for _, loan := range data.PoolLoans {
+		k.SetPoolLoan(ctx, loan)
+	}
 }
 
 // ExportGenesis writes the current store values
 // to a genesis file, which can be imported again
 // with InitGenesis
 func ExportGenesis(ctx sdk.Context, k Keeper) (data GenesisState) {
-	// TODO: Define logic for exporting state
-	return types.NewGenesisState()
+	return types.NewGenesisState(
+		k.GetParams(ctx),
+		k.GetAllDebts(ctx),
+		k.GetAllProposals(ctx),
+		k.GetAllPostedPrices(ctx),
+		k.GetAllArchivedDebts(ctx),
+		k.GetAllLedgerEntries(ctx),
+		k.GetAllTransfers(ctx),
+		k.GetAllCreditLines(ctx),
+		k.GetAllPools(ctx),
+		k.GetAllPoolLoans(ctx),
+		k.GetNextDebtSequence(ctx),
+	)
 }
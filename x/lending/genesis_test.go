@@ -0,0 +1,258 @@
+package lending
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/spoto/lending/x/lending/keeper"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenesis_ExportImportRoundTrip(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	oracle := sdk.AccAddress([]byte("oracle______________"))
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(20000))
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, k := keeper.SetupTestInput()
+	ctx = ctx.WithBlockHeight(10).WithBlockTime(blockTime)
+
+	params := types.DefaultParams()
+	params.Oracles = []sdk.AccAddress{oracle}
+	params.PoolCreators = []sdk.AccAddress{oracle}
+	params.TransferConsent = true
+	params.BlocksPerYear = 100 // a year of ten interest periods, within the max annual yield
+	params.RateModels = []types.RateModel{
+		types.NewJumpRateModel("foo", sdk.NewDecWithPrec(2, 2), sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(8, 1), sdk.OneDec()),
+	}
+	k.SetParams(ctx, params)
+
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount.Add(amount))))
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(800)))))
+
+	withTerms := types.NewDebt("A1", debtor, amount, creditor)
+	withTerms.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestCompound, 10, types.PeriodBlocks)
+	withTerms.MaturityTime = blockTime.Add(24 * time.Hour)
+	withTerms.GracePeriod = time.Hour
+	withTerms.Collateral = sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(500)))
+	withTerms.Repayment = types.NewCustomScheduleTerms(
+		types.NewInstallmentDue(blockTime.Add(12*time.Hour), sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10000)))),
+		types.NewInstallmentDue(blockTime.Add(24*time.Hour), sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10000)))),
+	)
+	require.NoError(t, k.DisburseDebt(ctx, withTerms))
+	require.NoError(t, k.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)))
+	require.NoError(t, k.TransferDebt(ctx, types.NewMsgTransferDebt("A1", creditor, oracle)))
+
+	repaid := types.NewDebt("A2", debtor, sdk.NewCoin("foo", sdk.ZeroInt()), creditor)
+	repaid.Status = types.StatusRepaid
+	k.SetArchivedDebt(ctx, types.NewArchivedDebt(repaid, 5, blockTime.Add(-time.Hour)))
+
+	require.NoError(t, k.ProposeDebt(ctx, types.NewDebt("P1", debtor, amount, creditor), 0))
+	require.NoError(t, k.CreateDebt(ctx, types.NewDebt(k.NextDebtID(ctx), debtor, sdk.NewCoin("foo", sdk.ZeroInt()), creditor)))
+	require.NoError(t, k.PostPrice(ctx, types.NewMsgPostPrice(oracle, "bar", sdk.NewDecWithPrec(15, 1))))
+
+	line := types.NewCreditLine("L1", creditor, debtor, sdk.NewCoin("foo", sdk.NewInt(5000)))
+	line.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+	line.ExpiryTime = blockTime.Add(48 * time.Hour)
+	require.NoError(t, k.OpenCreditLine(ctx, line))
+
+	require.NoError(t, k.CreatePool(ctx, types.NewMsgCreatePool(oracle, "foo", types.NoInterest())))
+	require.NoError(t, k.DepositToPool(ctx, types.NewMsgDepositToPool(debtor, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+	require.NoError(t, k.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.OneDec())))
+	borrowed := sdk.NewCoin("foo", sdk.NewInt(100))
+	require.NoError(t, k.BorrowFromPool(ctx, types.NewMsgBorrowFromPool("B1", debtor, borrowed, sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(300))))))
+
+	exported := ExportGenesis(ctx, k)
+	require.NoError(t, ValidateGenesis(exported))
+	require.Len(t, exported.Debts, 3)
+	require.Len(t, exported.ArchivedDebts, 1)
+	require.Len(t, exported.LedgerEntries, 1)
+	require.Len(t, exported.Transfers, 1)
+	require.Equal(t, uint64(2), exported.NextDebtSequence)
+	require.Len(t, exported.Proposals, 1)
+	require.Len(t, exported.Prices, 2)
+	require.Len(t, exported.CreditLines, 1)
+	require.Len(t, exported.Pools, 1)
+	require.Equal(t, sdk.NewInt(1000000), exported.Pools[0].TotalShares)
+	require.Equal(t, []types.PoolLoan{types.NewPoolLoan("foo", "B1")}, exported.PoolLoans)
+	require.Len(t, exported.Params.RateModels, 1)
+
+	// the state goes through JSON, as it does in a genesis file
+	bz := types.ModuleCdc.MustMarshalJSON(exported)
+	var imported GenesisState
+	types.ModuleCdc.MustUnmarshalJSON(bz, &imported)
+
+	_, newCtx, _, _, newKeeper := keeper.SetupTestInput()
+	newCtx = newCtx.WithBlockHeight(10).WithBlockTime(blockTime)
+	InitGenesis(newCtx, newKeeper, imported)
+
+	reexported := ExportGenesis(newCtx, newKeeper)
+	require.Equal(t, exported, reexported)
+	require.Equal(t, bz, types.ModuleCdc.MustMarshalJSON(reexported))
+}
+
+func TestGenesis_ValidateGenesis(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(100))
+	valid := types.NewDebt("A1", debtor, amount, creditor)
+
+	reflexive := types.NewDebt("A2", debtor, amount, debtor)
+
+	negative := types.NewDebt("A2", debtor, amount, creditor)
+	negative.Amount = types.DebtCoins{sdk.Coin{Denom: "foo", Amount: sdk.NewInt(-1)}}
+
+	noDebtor := types.NewDebt("A2", nil, amount, creditor)
+
+	tests := []struct {
+		name      string
+		debts     []types.Debt
+		proposals []types.DebtProposal
+		wantErr   bool
+	}{
+		{"default genesis", nil, nil, false},
+		{"valid debts and proposals", []types.Debt{valid}, []types.DebtProposal{types.NewDebtProposal(types.NewDebt("P1", debtor, amount, creditor), 10)}, false},
+		{"duplicate debt IDs", []types.Debt{valid, valid}, nil, true},
+		{"proposal with the ID of a debt", []types.Debt{valid}, []types.DebtProposal{types.NewDebtProposal(valid, 10)}, true},
+		{"reflexive debt", []types.Debt{reflexive}, nil, true},
+		{"negative debt", []types.Debt{negative}, nil, true},
+		{"debt with empty debtor", []types.Debt{noDebtor}, nil, true},
+		{"reflexive proposal", nil, []types.DebtProposal{types.NewDebtProposal(reflexive, 10)}, true},
+		{"assigned ID before the sequence", []types.Debt{types.NewDebt("seq-0", debtor, amount, creditor)}, nil, false},
+		{"assigned ID beyond the sequence", []types.Debt{types.NewDebt("seq-1", debtor, amount, creditor)}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := types.DefaultGenesisState()
+			data.Debts = tt.debts
+			data.Proposals = tt.proposals
+
+			err := ValidateGenesis(data)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenesis_ValidateCreditLines(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	limit := sdk.NewCoin("foo", sdk.NewInt(100))
+	valid := types.NewCreditLine("L1", lender, borrower, limit)
+
+	overdrawn := types.NewCreditLine("L1", lender, borrower, limit)
+	overdrawn.Drawn = sdk.NewInt(101)
+
+	tests := []struct {
+		name    string
+		debts   []types.Debt
+		lines   []types.CreditLine
+		wantErr bool
+	}{
+		{"valid credit line", nil, []types.CreditLine{valid}, false},
+		{"duplicate credit line IDs", nil, []types.CreditLine{valid, valid}, true},
+		{"credit line with the ID of a debt", []types.Debt{types.NewDebt("L1", borrower, limit, lender)}, []types.CreditLine{valid}, true},
+		{"credit line drawn beyond its limit", nil, []types.CreditLine{overdrawn}, true},
+		{"reflexive credit line", nil, []types.CreditLine{types.NewCreditLine("L1", lender, lender, limit)}, true},
+		{"assigned ID beyond the sequence", nil, []types.CreditLine{types.NewCreditLine("seq-1", lender, borrower, limit)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := types.DefaultGenesisState()
+			data.Debts = tt.debts
+			data.CreditLines = tt.lines
+
+			err := ValidateGenesis(data)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenesis_ValidatePoolLoans(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(100))
+	loan := types.NewDebt("B1", borrower, amount, types.PoolAddress("foo"))
+	notLent := types.NewDebt("B1", borrower, amount, lender)
+	pool := types.NewPool("foo", types.NoInterest())
+
+	tests := []struct {
+		name    string
+		debts   []types.Debt
+		pools   []types.Pool
+		loans   []types.PoolLoan
+		wantErr bool
+	}{
+		{"valid pool loan", []types.Debt{loan}, []types.Pool{pool}, []types.PoolLoan{types.NewPoolLoan("foo", "B1")}, false},
+		{"duplicate pool loan", []types.Debt{loan}, []types.Pool{pool},
+			[]types.PoolLoan{types.NewPoolLoan("foo", "B1"), types.NewPoolLoan("foo", "B1")}, true},
+		{"loan of an unknown pool", []types.Debt{loan}, nil, []types.PoolLoan{types.NewPoolLoan("foo", "B1")}, true},
+		{"loan of an unknown debt", nil, []types.Pool{pool}, []types.PoolLoan{types.NewPoolLoan("foo", "B1")}, true},
+		{"loan not owed to the pool", []types.Debt{notLent}, []types.Pool{pool}, []types.PoolLoan{types.NewPoolLoan("foo", "B1")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := types.DefaultGenesisState()
+			data.Debts = tt.debts
+			data.Pools = tt.pools
+			data.PoolLoans = tt.loans
+
+			err := ValidateGenesis(data)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGenesis_ValidateTransfers(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	buyer := sdk.AccAddress([]byte("buyer_______________"))
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+
+	tests := []struct {
+		name        string
+		newCreditor sdk.AccAddress
+		wantErr     bool
+	}{
+		{"transfer to another address", buyer, false},
+		{"transfer to the debtor", debtor, true},
+		{"transfer to the module account", supply.NewModuleAddress(types.ModuleName), true},
+		{"transfer to the pool of its denom", types.PoolAddress("foo"), true},
+		{"transfer to another pool", types.PoolAddress("bar"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := types.DefaultGenesisState()
+			data.Debts = []types.Debt{debt}
+			data.Pools = []types.Pool{types.NewPool("bar", types.NoInterest())}
+			data.Transfers = []types.DebtTransfer{types.NewDebtTransfer("A1", creditor, tt.newCreditor)}
+
+			err := ValidateGenesis(data)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
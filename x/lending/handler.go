@@ -16,6 +16,40 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgPayDebt(ctx, keeper, msg)
 		case types.MsgChangeDebt:
 			return handleMsgChangeDebt(ctx, keeper, msg)
+		case types.MsgProposeDebt:
+			return handleMsgProposeDebt(ctx, keeper, msg)
+		case types.MsgAcceptDebt:
+			return handleMsgAcceptDebt(ctx, keeper, msg)
+		case types.MsgRejectDebt:
+			return handleMsgRejectDebt(ctx, keeper, msg)
+		case types.MsgWithdrawProposal:
+			return handleMsgWithdrawProposal(ctx, keeper, msg)
+		case types.MsgClaimCollateral:
+			return handleMsgClaimCollateral(ctx, keeper, msg)
+		case types.MsgPostPrice:
+			return handleMsgPostPrice(ctx, keeper, msg)
+		case types.MsgLiquidate:
+			return handleMsgLiquidate(ctx, keeper, msg)
+		case types.MsgTransferDebt:
+			return handleMsgTransferDebt(ctx, keeper, msg)
+		case types.MsgConsentTransfer:
+			return handleMsgConsentTransfer(ctx, keeper, msg)
+		case types.MsgOpenCreditLine:
+			return handleMsgOpenCreditLine(ctx, keeper, msg)
+		case types.MsgDrawCredit:
+			return handleMsgDrawCredit(ctx, keeper, msg)
+		case types.MsgRepayCredit:
+			return handleMsgRepayCredit(ctx, keeper, msg)
+		case types.MsgCloseCreditLine:
+			return handleMsgCloseCreditLine(ctx, keeper, msg)
+		case types.MsgCreatePool:
+			return handleMsgCreatePool(ctx, keeper, msg)
+		case types.MsgDepositToPool:
+			return handleMsgDepositToPool(ctx, keeper, msg)
+		case types.MsgWithdrawFromPool:
+			return handleMsgWithdrawFromPool(ctx, keeper, msg)
+		case types.MsgBorrowFromPool:
+			return handleMsgBorrowFromPool(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized %s message type: %v", types.ModuleName, msg.Type())
 			return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, errMsg)
@@ -26,26 +60,198 @@ func NewHandler(keeper Keeper) sdk.Handler {
 func handleMsgChangeDebt(ctx sdk.Context, keeper Keeper, msg types.MsgChangeDebt) (*sdk.Result, error) {
 	err := keeper.ChangeDebt(ctx, msg)
 	if err != nil {
-		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, err.Error())
+		return nil, err
 	}
 
-	return &sdk.Result{Log: "Debt changed successfully"}, nil
+	return &sdk.Result{Log: "Debt changed successfully", Events: ctx.EventManager().Events()}, nil
 }
 
 func handleMsgCreateDebt(ctx sdk.Context, keeper Keeper, msg types.MsgCreateDebt) (*sdk.Result, error) {
-	err := keeper.CreateDebt(ctx, types.Debt(msg))
+	debt := types.Debt(msg)
+	if debt.ID == "" {
+		debt.ID = keeper.NextDebtID(ctx)
+	}
+
+	err := keeper.DisburseDebt(ctx, debt)
 	if err != nil {
-		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, err.Error())
+		return nil, err
 	}
 
-	return &sdk.Result{Log: "Debt created successfully"}, nil
+	return &sdk.Result{Data: []byte(debt.ID), Log: "Debt created successfully with ID " + debt.ID, Events: ctx.EventManager().Events()}, nil
 }
 
 func handleMsgPayDebt(ctx sdk.Context, keeper Keeper, msg types.MsgPayDebt) (*sdk.Result, error) {
 	err := keeper.PayDebt(ctx, msg)
 	if err != nil {
-		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, err.Error())
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Debt financed successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgProposeDebt(ctx sdk.Context, keeper Keeper, msg types.MsgProposeDebt) (*sdk.Result, error) {
+	debt := msg.Debt
+	if debt.ID == "" {
+		debt.ID = keeper.NextDebtID(ctx)
+	}
+
+	err := keeper.ProposeDebt(ctx, debt, msg.Expiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Data: []byte(debt.ID), Log: "Debt proposed successfully with ID " + debt.ID, Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgAcceptDebt(ctx sdk.Context, keeper Keeper, msg types.MsgAcceptDebt) (*sdk.Result, error) {
+	err := keeper.AcceptDebt(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Debt accepted successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgRejectDebt(ctx sdk.Context, keeper Keeper, msg types.MsgRejectDebt) (*sdk.Result, error) {
+	err := keeper.RejectDebt(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Debt rejected successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgWithdrawProposal(ctx sdk.Context, keeper Keeper, msg types.MsgWithdrawProposal) (*sdk.Result, error) {
+	err := keeper.WithdrawProposal(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Proposal withdrawn successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgClaimCollateral(ctx sdk.Context, keeper Keeper, msg types.MsgClaimCollateral) (*sdk.Result, error) {
+	err := keeper.ClaimCollateral(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Collateral claimed successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgPostPrice(ctx sdk.Context, keeper Keeper, msg types.MsgPostPrice) (*sdk.Result, error) {
+	err := keeper.PostPrice(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Price posted successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgLiquidate(ctx sdk.Context, keeper Keeper, msg types.MsgLiquidate) (*sdk.Result, error) {
+	err := keeper.Liquidate(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Debt liquidated successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgTransferDebt(ctx sdk.Context, keeper Keeper, msg types.MsgTransferDebt) (*sdk.Result, error) {
+	err := keeper.TransferDebt(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Debt transfer requested successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgConsentTransfer(ctx sdk.Context, keeper Keeper, msg types.MsgConsentTransfer) (*sdk.Result, error) {
+	err := keeper.ConsentTransfer(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Debt transferred successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgOpenCreditLine(ctx sdk.Context, keeper Keeper, msg types.MsgOpenCreditLine) (*sdk.Result, error) {
+	line := msg.Line
+	if line.ID == "" {
+		line.ID = keeper.NextDebtID(ctx)
+	}
+
+	err := keeper.OpenCreditLine(ctx, line)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Data: []byte(line.ID), Log: "Credit line opened successfully with ID " + line.ID, Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgDrawCredit(ctx sdk.Context, keeper Keeper, msg types.MsgDrawCredit) (*sdk.Result, error) {
+	err := keeper.DrawCredit(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Credit drawn successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgRepayCredit(ctx sdk.Context, keeper Keeper, msg types.MsgRepayCredit) (*sdk.Result, error) {
+	err := keeper.RepayCredit(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Credit repaid successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgCloseCreditLine(ctx sdk.Context, keeper Keeper, msg types.MsgCloseCreditLine) (*sdk.Result, error) {
+	err := keeper.CloseCreditLine(ctx, msg)
+	if err != nil {
+		return nil, err
 	}
 
-	return &sdk.Result{Log: "Debt financed successfully"}, nil
-}
\ No newline at end of file
+	return &sdk.Result{Log: "Credit line closed successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgCreatePool(ctx sdk.Context, keeper Keeper, msg types.MsgCreatePool) (*sdk.Result, error) {
+	err := keeper.CreatePool(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Pool created successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgDepositToPool(ctx sdk.Context, keeper Keeper, msg types.MsgDepositToPool) (*sdk.Result, error) {
+	err := keeper.DepositToPool(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Deposited to pool successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgWithdrawFromPool(ctx sdk.Context, keeper Keeper, msg types.MsgWithdrawFromPool) (*sdk.Result, error) {
+	err := keeper.WithdrawFromPool(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Log: "Withdrawn from pool successfully", Events: ctx.EventManager().Events()}, nil
+}
+
+func handleMsgBorrowFromPool(ctx sdk.Context, keeper Keeper, msg types.MsgBorrowFromPool) (*sdk.Result, error) {
+	if msg.ID == "" {
+		msg.ID = keeper.NextDebtID(ctx)
+	}
+
+	err := keeper.BorrowFromPool(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sdk.Result{Data: []byte(msg.ID), Log: "Borrowed from pool successfully with debt ID " + msg.ID, Events: ctx.EventManager().Events()}, nil
+}
@@ -0,0 +1,120 @@
+package lending
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/keeper"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_AssignsDebtIDs(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(100))
+
+	_, ctx, _, bankKeeper, k := keeper.SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+	handler := NewHandler(k)
+
+	// debts without ID get the next one of the sequence
+	res, err := handler(ctx, types.NewMsgCreateDebt(types.NewDebt("", debtor, amount, creditor)))
+	require.NoError(t, err)
+	require.Equal(t, "seq-1", string(res.Data))
+
+	res, err = handler(ctx, types.NewMsgProposeDebt(types.NewDebt("", debtor, amount, creditor), 0))
+	require.NoError(t, err)
+	require.Equal(t, "seq-2", string(res.Data))
+
+	// user-chosen IDs are kept and do not consume the sequence
+	res, err = handler(ctx, types.NewMsgCreateDebt(types.NewDebt("A1", debtor, amount, creditor)))
+	require.NoError(t, err)
+	require.Equal(t, "A1", string(res.Data))
+
+	res, err = handler(ctx, types.NewMsgCreateDebt(types.NewDebt("", debtor, amount, creditor)))
+	require.NoError(t, err)
+	require.Equal(t, "seq-3", string(res.Data))
+
+	// credit lines share the sequence of the debts
+	res, err = handler(ctx, types.NewMsgOpenCreditLine(types.NewCreditLine("", creditor, debtor, amount)))
+	require.NoError(t, err)
+	require.Equal(t, "seq-4", string(res.Data))
+
+	require.Len(t, k.GetAllDebts(ctx), 3)
+	require.Len(t, k.GetAllProposals(ctx), 1)
+	require.Len(t, k.GetAllCreditLines(ctx), 1)
+	require.Equal(t, uint64(5), k.GetNextDebtSequence(ctx))
+}
+
+func TestMsgCreateDebt_ReservedIDs(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(100))
+
+	require.NoError(t, types.NewMsgCreateDebt(types.NewDebt("", debtor, amount, creditor)).ValidateBasic())
+	require.NoError(t, types.NewMsgCreateDebt(types.NewDebt("A1", debtor, amount, creditor)).ValidateBasic())
+	require.Error(t, types.NewMsgCreateDebt(types.NewDebt("seq-7", debtor, amount, creditor)).ValidateBasic())
+	require.Error(t, types.NewMsgProposeDebt(types.NewDebt("seq-7", debtor, amount, creditor), 0).ValidateBasic())
+}
+
+func TestHandler_ErrorCodes(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(100))
+
+	_, ctx, _, bankKeeper, k := keeper.SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(150)))))
+	handler := NewHandler(k)
+
+	_, err := handler(ctx, types.NewMsgCreateDebt(types.NewDebt("A1", debtor, amount, creditor)))
+	require.NoError(t, err)
+
+	longSchedule := types.NewDebt("A3", debtor, amount, creditor)
+	longSchedule.Repayment = types.NewScheduleTerms(types.ScheduleEqual, types.DefaultMaxInstallments+1, time.Hour)
+
+	tests := []struct {
+		name string
+		msg  sdk.Msg
+		want *sdkErr.Error
+	}{
+		{"missing debt", types.NewMsgPayDebt("B1", amount, debtor), types.ErrDebtNotFound},
+		{"missing proposal", types.NewMsgAcceptDebt("B1", debtor), types.ErrProposalNotFound},
+		{"used ID", types.NewMsgCreateDebt(types.NewDebt("A1", debtor, amount, creditor)), types.ErrDuplicateID},
+		{"someone else's debt", types.NewMsgPayDebt("A1", amount, other), types.ErrUnauthorized},
+		{"larger amount", types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(200)), creditor), types.ErrInvalidAmount},
+		{"not an oracle", types.NewMsgPostPrice(other, "foo", sdk.OneDec()), types.ErrNotOracle},
+		{"not enough funds", types.NewMsgCreateDebt(types.NewDebt("A2", debtor, amount, creditor)), sdkErr.ErrInsufficientFunds},
+		{"other denom", types.NewMsgChangeDebt("A1", sdk.NewCoin("bar", sdk.NewInt(10)), creditor), types.ErrDenomMismatch},
+		{"too many installments", types.NewMsgCreateDebt(longSchedule), types.ErrTooManyInstallments},
+		{"not a pool creator", types.NewMsgCreatePool(other, "foo", types.NoInterest()), types.ErrNotPoolCreator},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(ctx, tt.msg)
+			require.True(t, tt.want.Is(err), err)
+
+			codespace, code, _ := sdkErr.ABCIInfo(err, false)
+			require.Equal(t, tt.want.Codespace(), codespace)
+			require.Equal(t, tt.want.ABCICode(), code)
+		})
+	}
+}
+
+func TestMsgs_MissingAmount(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	// amounts left out of JSON requests are decoded without an integer
+	missing := sdk.Coin{Denom: "foo"}
+
+	require.Error(t, types.NewMsgPayDebt("A1", missing, debtor).ValidateBasic())
+	require.Error(t, types.NewMsgChangeDebt("A1", missing, creditor).ValidateBasic())
+	require.Error(t, types.NewMsgCreateDebt(types.NewDebt("A1", debtor, missing, creditor)).ValidateBasic())
+}
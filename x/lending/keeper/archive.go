@@ -0,0 +1,151 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// closed debts leave the active debts for the archive, where they are also
+// indexed by closing time, so that the expired ones can be pruned in order
+const (
+	archiveStorePrefix     = ":archive:"
+	archiveTimeIndexPrefix = ":archivetime:"
+)
+
+// debtArchiveVersion is increased whenever closed debts must be moved again to the archive
+const debtArchiveVersion uint64 = 1
+
+var debtArchiveVersionKey = []byte(":debtarchiveversion:")
+
+func getArchiveStoreKey(ID string) []byte {
+	return []byte(archiveStorePrefix + ID)
+}
+
+func getArchiveTimeIndexKey(closedTime time.Time, ID string) []byte {
+	return append(getArchiveTimeIndexPrefix(closedTime), ID...)
+}
+
+func getArchiveTimeIndexPrefix(closedTime time.Time) []byte {
+	return append([]byte(archiveTimeIndexPrefix), sdk.FormatTimeBytes(closedTime)...)
+}
+
+// isUsedID yields true if a debt, a proposal, an archived debt or a credit line has the given ID
+func (keeper Keeper) isUsedID(ctx sdk.Context, id string) bool {
+	store := ctx.KVStore(keeper.storeKey)
+	return store.Has(getDebtStoreKey(id)) || store.Has(getProposalStoreKey(id)) || store.Has(getArchiveStoreKey(id)) ||
+		store.Has(getCreditLineStoreKey(id))
+}
+
+// closeDebt moves a debt that has just been closed from the active debts to the archive.
+// Its pending transfer, if any, cannot take place anymore, and if it was drawn from
+// a pool, it does not count towards what the pool has lent anymore
+func (keeper Keeper) closeDebt(ctx sdk.Context, debt types.Debt) error {
+	old, err := keeper.GetDebt(ctx, debt.ID)
+	if err != nil {
+		return err
+	}
+
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(getDebtStoreKey(debt.ID))
+	keeper.deleteDebtIndexes(ctx, old)
+	keeper.deletePoolLoan(ctx, old)
+	keeper.deleteTransfer(ctx, debt.ID)
+
+	keeper.SetArchivedDebt(ctx, types.NewArchivedDebt(debt, ctx.BlockHeight(), ctx.BlockTime()))
+	return nil
+}
+
+// SetArchivedDebt stores a closed debt in the archive
+func (keeper Keeper) SetArchivedDebt(ctx sdk.Context, archived types.ArchivedDebt) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getArchiveStoreKey(archived.Debt.ID), keeper.cdc.MustMarshalBinaryBare(&archived))
+	store.Set(getArchiveTimeIndexKey(archived.ClosedTime, archived.Debt.ID), []byte{})
+}
+
+// GetArchivedDebt yields the closed debt with the given ID
+func (keeper Keeper) GetArchivedDebt(ctx sdk.Context, id string) (types.ArchivedDebt, error) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	archiveKey := getArchiveStoreKey(id)
+	if !store.Has(archiveKey) {
+		return types.ArchivedDebt{}, sdkErr.Wrapf(types.ErrDebtNotFound, "cannot find archived debt with ID %s", id)
+	}
+
+	var archived types.ArchivedDebt
+	keeper.cdc.MustUnmarshalBinaryBare(store.Get(archiveKey), &archived)
+	return archived, nil
+}
+
+// GetAllArchivedDebts yields all closed debts still in the archive
+func (keeper Keeper) GetAllArchivedDebts(ctx sdk.Context) []types.ArchivedDebt {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, []byte(archiveStorePrefix))
+	defer ri.Close()
+
+	archived := []types.ArchivedDebt{}
+	for ; ri.Valid(); ri.Next() {
+		var debt types.ArchivedDebt
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &debt)
+		archived = append(archived, debt)
+	}
+
+	return archived
+}
+
+// PruneArchive deletes the archived debts closed longer than the retention
+// period ago, together with their ledger. Nothing is pruned if the retention period is zero
+func (keeper Keeper) PruneArchive(ctx sdk.Context) {
+	retention := keeper.GetParams(ctx).ArchiveRetention
+	if retention == 0 {
+		return
+	}
+
+	store := ctx.KVStore(keeper.storeKey)
+	start := []byte(archiveTimeIndexPrefix)
+	end := getArchiveTimeIndexPrefix(ctx.BlockTime().Add(-retention))
+	ri := store.Iterator(start, end)
+
+	// keys cannot be deleted while iterating
+	var expired [][]byte
+	for ; ri.Valid(); ri.Next() {
+		expired = append(expired, ri.Key())
+	}
+	ri.Close()
+
+	timeBytes := len(sdk.FormatTimeBytes(time.Time{}))
+	for _, key := range expired {
+		id := string(key[len(archiveTimeIndexPrefix)+timeBytes:])
+		store.Delete(getArchiveStoreKey(id))
+		store.Delete(key)
+		keeper.deleteLedger(ctx, id)
+	}
+}
+
+// MigrateDebtArchive moves to the archive the closed debts left among
+// the active ones by a version of the module without archive. Since their
+// closing block is unknown, they are considered closed in the current block
+func (keeper Keeper) MigrateDebtArchive(ctx sdk.Context) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	if store.Has(debtArchiveVersionKey) {
+		var version uint64
+		keeper.cdc.MustUnmarshalBinaryBare(store.Get(debtArchiveVersionKey), &version)
+		if version >= debtArchiveVersion {
+			return
+		}
+	}
+
+	closed := keeper.getDebts(ctx, func(debt types.Debt) bool {
+		return debt.Status.IsClosed()
+	})
+	for _, debt := range closed {
+		if err := keeper.closeDebt(ctx, debt); err != nil {
+			panic(err)
+		}
+	}
+
+	store.Set(debtArchiveVersionKey, keeper.cdc.MustMarshalBinaryBare(debtArchiveVersion))
+}
@@ -0,0 +1,181 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestKeeper_ClosedDebtsAreArchived(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+	closedTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(7).WithBlockTime(closedTime)
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(amount)))
+
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A1", debtor, amount, creditor)))
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A2", debtor, amount, creditor)))
+
+	// the debtor pays part of A1, the creditor forgives the rest
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(600)), debtor)))
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(400)), creditor)))
+
+	// closed debts leave the active debts and their indexes
+	require.Equal(t, []string{"A2"}, debtIDs(keeper.GetAllDebts(ctx)))
+	require.Equal(t, []string{"A2"}, debtIDs(keeper.GetDebtorDebts(ctx, debtor)))
+	require.Equal(t, []string{"A2"}, debtIDs(keeper.GetCreditorDebts(ctx, creditor)))
+
+	archived, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), archived.ClosedHeight)
+	require.True(t, closedTime.Equal(archived.ClosedTime))
+	require.Equal(t, types.StatusForgiven, archived.Debt.Status)
+	require.Equal(t, types.Settlement{
+		PrincipalPaid: types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(600))),
+		InterestPaid:  types.NewDebtCoins(sdk.NewCoin("foo", sdk.ZeroInt())),
+		Forgiven:      types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(400))),
+		FeesPaid:      types.NewDebtCoins(sdk.NewCoin("foo", sdk.ZeroInt())),
+	}, archived.Debt.Settlement)
+
+	// the IDs of archived debts cannot be used again
+	require.Error(t, keeper.CreateDebt(ctx, types.NewDebt("A1", debtor, amount, creditor)))
+	require.Error(t, keeper.ProposeDebt(ctx, types.NewDebt("A1", debtor, amount, creditor), 0))
+}
+
+func TestKeeper_PruneArchive(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	archive := func(id string, closedTime time.Time) types.ArchivedDebt {
+		debt := types.NewDebt(id, debtor, sdk.NewCoin("foo", sdk.ZeroInt()), creditor)
+		debt.Status = types.StatusRepaid
+		return types.NewArchivedDebt(debt, 1, closedTime)
+	}
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		want      []string
+	}{
+		{"kept forever", 0, []string{"A1", "A2", "A3"}},
+		{"kept for a day", 24 * time.Hour, []string{"A2", "A3"}},
+		{"kept for an hour", time.Hour, []string{"A3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+			ctx = ctx.WithBlockTime(now)
+
+			params := types.DefaultParams()
+			params.ArchiveRetention = tt.retention
+			keeper.SetParams(ctx, params)
+
+			keeper.SetArchivedDebt(ctx, archive("A1", now.Add(-48*time.Hour)))
+			keeper.SetArchivedDebt(ctx, archive("A2", now.Add(-2*time.Hour)))
+			keeper.SetArchivedDebt(ctx, archive("A3", now))
+
+			keeper.PruneArchive(ctx)
+
+			var ids []string
+			for _, archived := range keeper.GetAllArchivedDebts(ctx) {
+				ids = append(ids, archived.Debt.ID)
+			}
+			require.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestKeeper_MigrateDebtArchive(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	// a store written before the archive existed keeps its closed debts among the active ones
+	repaid := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.ZeroInt()), creditor)
+	repaid.Status = types.StatusRepaid
+	require.NoError(t, keeper.CreateDebt(ctx, repaid))
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A2", debtor, sdk.NewCoin("foo", sdk.NewInt(10)), creditor)))
+
+	keeper.MigrateDebtArchive(ctx)
+
+	require.Equal(t, []string{"A2"}, debtIDs(keeper.GetAllDebts(ctx)))
+	require.Equal(t, []string{"A2"}, debtIDs(keeper.GetDebtorDebts(ctx, debtor)))
+	_, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+}
+
+func Test_queryGetDebtHistory(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	archive := func(id string, debtor, creditor sdk.AccAddress, status types.DebtStatus) types.ArchivedDebt {
+		debt := types.NewDebt(id, debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+		debt.Amount = debt.Amount.Zero()
+		debt.Status = status
+		return types.NewArchivedDebt(debt, 1, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	repaid := archive("A1", debtor, creditor, types.StatusRepaid)
+	forgiven := archive("A2", debtor, creditor, types.StatusForgiven)
+	unrelated := archive("A3", other, creditor, types.StatusRepaid)
+
+	tests := []struct {
+		name   string
+		path   []string
+		params func(params *types.QueryDebtsParams)
+		want   []types.ArchivedDebt
+	}{
+		{"all archived debts", nil, nil, []types.ArchivedDebt{repaid, forgiven, unrelated}},
+		{"archived debts of an address", []string{debtor.String()}, nil, []types.ArchivedDebt{repaid, forgiven}},
+		{"forgiven debts", nil, func(p *types.QueryDebtsParams) { p.Status = types.StatusForgiven }, []types.ArchivedDebt{forgiven}},
+		{"by principal", nil, func(p *types.QueryDebtsParams) { p.MinAmount = sdk.NewInt(100) }, []types.ArchivedDebt{repaid, forgiven, unrelated}},
+		{"second page", nil, func(p *types.QueryDebtsParams) { p.Page, p.Limit = 2, 2 }, []types.ArchivedDebt{unrelated}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, _, keeper := SetupTestInput()
+			keeper.SetArchivedDebt(ctx, repaid)
+			keeper.SetArchivedDebt(ctx, forgiven)
+			keeper.SetArchivedDebt(ctx, unrelated)
+
+			// archived debts are not among the active ones
+			require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("B1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+
+			var req abci.RequestQuery
+			if tt.params != nil {
+				params := types.NewQueryDebtsParams(1, 0)
+				tt.params(&params)
+				req.Data = cdc.MustMarshalJSON(params)
+			}
+
+			result, err := queryGetDebtHistory(ctx, tt.path, req, keeper)
+			require.NoError(t, err)
+
+			var archived []types.ArchivedDebt
+			cdc.MustUnmarshalJSON(result, &archived)
+			require.Equal(t, tt.want, archived)
+		})
+	}
+}
+
+// debtIDs yields the IDs of the debts, in order
+func debtIDs(debts []types.Debt) []string {
+	ids := []string{}
+	for _, debt := range debts {
+		ids = append(ids, debt.ID)
+	}
+
+	return ids
+}
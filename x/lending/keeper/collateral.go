@@ -0,0 +1,85 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// lockCollateral moves the collateral of a debt from its debtor into the module account
+func (keeper Keeper) lockCollateral(ctx sdk.Context, debt types.Debt) error {
+	if debt.Collateral.Empty() {
+		return nil
+	}
+
+	return keeper.supplyKeeper.SendCoinsFromAccountToModule(ctx, debt.Debtor, types.ModuleName, debt.Collateral)
+}
+
+// releaseCollateral gives the collateral of a debt back to its debtor
+func (keeper Keeper) releaseCollateral(ctx sdk.Context, debt *types.Debt) error {
+	if debt.Collateral.Empty() {
+		return nil
+	}
+
+	if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, debt.Debtor, debt.Collateral); err != nil {
+		return err
+	}
+
+	debt.Collateral = sdk.NewCoins()
+	return nil
+}
+
+// ClaimCollateral gives the collateral of a defaulted debt to its creditor
+func (keeper Keeper) ClaimCollateral(ctx sdk.Context, msg types.MsgClaimCollateral) error {
+	debt, err := keeper.GetDebt(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Creditor.Equals(debt.Creditor) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the debt with ID %s is not yours", msg.ID)
+	}
+
+	if debt.Status != types.StatusDefaulted {
+		return sdkErr.Wrapf(types.ErrInvalidDebtStatus, "the debt with ID %s is %s, not %s", msg.ID, debt.Status, types.StatusDefaulted)
+	}
+
+	if debt.Collateral.Empty() {
+		return sdkErr.Wrapf(types.ErrNoCollateral, "the debt with ID %s has no collateral to claim", msg.ID)
+	}
+
+	if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, debt.Creditor, debt.Collateral); err != nil {
+		return err
+	}
+
+	claimed := debt.Collateral
+	debt.Collateral = sdk.NewCoins()
+	if err := keeper.updateDebt(ctx, debt); err != nil {
+		return err
+	}
+
+	keeper.emitDebtEvent(ctx, types.ActionClaimCollateral, debt, claimed.String())
+	return nil
+}
+
+// GetLockedCoins yields the coins that the module account must hold in custody: the collateral
+// of the debts, the principal of the pending proposals and what is left to draw from escrowed credit lines
+func (keeper Keeper) GetLockedCoins(ctx sdk.Context) sdk.Coins {
+	locked := sdk.NewCoins()
+
+	for _, debt := range keeper.GetAllDebts(ctx) {
+		locked = locked.Add(debt.Collateral...)
+	}
+
+	for _, proposal := range keeper.GetAllProposals(ctx) {
+		locked = locked.Add(proposal.Debt.Amount.Coins()...)
+	}
+
+	for _, line := range keeper.GetAllCreditLines(ctx) {
+		if line.Escrowed {
+			locked = locked.Add(line.Available())
+		}
+	}
+
+	return locked
+}
@@ -0,0 +1,147 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_CollateralLockedAndReleased(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(20000))
+	collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(500)))
+
+	debt := types.NewDebt("A1", debtor, amount, creditor)
+	debt.Collateral = collateral
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount)))
+	require.NoError(t, keeper.ProposeDebt(ctx, debt, 0))
+
+	// the debtor cannot accept a debt it cannot back with collateral
+	require.Error(t, keeper.AcceptDebt(ctx, types.NewMsgAcceptDebt(debt.ID, debtor)))
+	require.Len(t, keeper.GetAllProposals(ctx), 1)
+
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, collateral))
+	require.NoError(t, keeper.AcceptDebt(ctx, types.NewMsgAcceptDebt(debt.ID, debtor)))
+
+	require.True(t, keeper.GetModuleAccountCoins(ctx).IsEqual(collateral))
+	require.True(t, authKeeper.GetAccount(ctx, debtor).GetCoins().IsEqual(sdk.NewCoins(amount)))
+	_, broken := LockedCoinsAreHeld(keeper)(ctx)
+	require.False(t, broken)
+
+	// a partial payment does not release the collateral
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt(debt.ID, sdk.NewCoin("foo", sdk.NewInt(10000)), debtor)))
+	require.True(t, keeper.GetModuleAccountCoins(ctx).IsEqual(collateral))
+
+	// the full payment releases the collateral
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt(debt.ID, sdk.NewCoin("foo", sdk.NewInt(10000)), debtor)))
+	require.True(t, keeper.GetModuleAccountCoins(ctx).Empty())
+	require.True(t, authKeeper.GetAccount(ctx, debtor).GetCoins().IsEqual(collateral))
+
+	archived, err := keeper.GetArchivedDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, archived.Debt.Collateral.Empty())
+	_, broken = LockedCoinsAreHeld(keeper)(ctx)
+	require.False(t, broken)
+}
+
+func TestKeeper_LockedCoinsInOtherDenoms(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Collateral = sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(500)))
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+	// the module account holds as many coins as are locked, but in a denom that nothing locks
+	moduleAddress := keeper.supplyKeeper.GetModuleAddress(types.ModuleName)
+	require.NoError(t, bankKeeper.SetCoins(ctx, moduleAddress, sdk.NewCoins(sdk.NewCoin("baz", sdk.NewInt(500)))))
+
+	var broken bool
+	require.NotPanics(t, func() {
+		_, broken = LockedCoinsAreHeld(keeper)(ctx)
+	})
+	require.True(t, broken)
+}
+
+func TestKeeper_ClaimCollateral(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(20000))
+	collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(500)))
+
+	tests := []struct {
+		name       string
+		status     types.DebtStatus
+		collateral sdk.Coins
+		claimer    sdk.AccAddress
+		wantErr    bool
+	}{
+		{
+			"claim collateral of defaulted debt",
+			types.StatusDefaulted,
+			collateral,
+			creditor,
+			false,
+		},
+		{
+			"claim collateral of overdue debt",
+			types.StatusOverdue,
+			collateral,
+			creditor,
+			true,
+		},
+		{
+			"claim collateral of somebody else's debt",
+			types.StatusDefaulted,
+			collateral,
+			debtor,
+			true,
+		},
+		{
+			"claim collateral of debt without collateral",
+			types.StatusDefaulted,
+			nil,
+			creditor,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+
+			debt := types.NewDebt("A1", debtor, amount, creditor)
+			debt.Collateral = tt.collateral
+			require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount)))
+			require.NoError(t, bankKeeper.SetCoins(ctx, debtor, tt.collateral))
+			require.NoError(t, keeper.DisburseDebt(ctx, debt))
+
+			debt, err := keeper.GetDebt(ctx, debt.ID)
+			require.NoError(t, err)
+			debt.Status = tt.status
+			require.NoError(t, keeper.updateDebt(ctx, debt))
+
+			err = keeper.ClaimCollateral(ctx, types.NewMsgClaimCollateral(debt.ID, tt.claimer))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, keeper.GetModuleAccountCoins(ctx).IsEqual(tt.collateral))
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, keeper.GetModuleAccountCoins(ctx).Empty())
+			require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(collateral))
+
+			_, broken := LockedCoinsAreHeld(keeper)(ctx)
+			require.False(t, broken)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	"github.com/cosmos/cosmos-sdk/x/supply"
 	"github.com/spoto/lending/x/lending/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
@@ -20,6 +21,7 @@ func SetupTestInput() (cdc *codec.Codec, ctx sdk.Context, ak auth.AccountKeeper,
 	keys := sdk.NewKVStoreKeys(
 		auth.StoreKey,
 		params.StoreKey,
+		supply.StoreKey,
 		types.StoreKey,
 	)
 	tKeys := sdk.NewTransientStoreKeys(params.TStoreKey)
@@ -39,7 +41,13 @@ func SetupTestInput() (cdc *codec.Codec, ctx sdk.Context, ak auth.AccountKeeper,
 	pk := params.NewKeeper(cdc, keys[params.StoreKey], tKeys[params.TStoreKey])
 	ak = auth.NewAccountKeeper(cdc, keys[auth.StoreKey], pk.Subspace(auth.DefaultParamspace), auth.ProtoBaseAccount)
 	bk = bank.NewBaseKeeper(ak, pk.Subspace(bank.DefaultParamspace), nil)
-	k = NewKeeper(keys[types.StoreKey], bk, cdc)
+	maccPerms := map[string][]string{
+		types.ModuleName: {supply.Minter, supply.Burner},
+	}
+	sk := supply.NewKeeper(cdc, keys[supply.StoreKey], ak, bk, maccPerms)
+	sk.SetSupply(ctx, supply.NewSupply(sdk.NewCoins()))
+	k = NewKeeper(keys[types.StoreKey], bk, sk, pk.Subspace(types.DefaultParamspace), cdc)
+	k.SetParams(ctx, types.DefaultParams())
 
 	return
 }
@@ -50,6 +58,7 @@ func testCodec() *codec.Codec {
 	cdc.RegisterInterface((*crypto.PubKey)(nil), nil)
 	auth.RegisterCodec(cdc)
 	bank.RegisterCodec(cdc)
+	supply.RegisterCodec(cdc)
 	types.RegisterCodec(cdc)
 	cdc.Seal()
 
@@ -0,0 +1,301 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// credit lines share the namespace of IDs of the debts
+const creditLineStorePrefix = ":creditline:"
+
+func getCreditLineStoreKey(ID string) []byte {
+	return []byte(creditLineStorePrefix + ID)
+}
+
+// GetCreditLine yields the open credit line with the given ID
+func (keeper Keeper) GetCreditLine(ctx sdk.Context, id string) (types.CreditLine, error) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	lineKey := getCreditLineStoreKey(id)
+	if !store.Has(lineKey) {
+		return types.CreditLine{}, sdkErr.Wrapf(types.ErrCreditLineNotFound, "cannot find credit line with ID %s", id)
+	}
+
+	var line types.CreditLine
+	keeper.cdc.MustUnmarshalBinaryBare(store.Get(lineKey), &line)
+	return line, nil
+}
+
+// SetCreditLine stores a credit line, without moving any coin
+func (keeper Keeper) SetCreditLine(ctx sdk.Context, line types.CreditLine) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	// the end of the interest period of the line might have moved
+	if old, err := keeper.GetCreditLine(ctx, line.ID); err == nil {
+		keeper.deleteCreditLineIndexes(ctx, old)
+	}
+	store.Set(getCreditLineStoreKey(line.ID), keeper.cdc.MustMarshalBinaryBare(&line))
+	keeper.setCreditLineIndexes(ctx, line)
+}
+
+// OpenCreditLine grants a credit line with nothing drawn yet. The limit of
+// an escrowed line moves from the lender to the module account
+func (keeper Keeper) OpenCreditLine(ctx sdk.Context, line types.CreditLine) error {
+	if keeper.isUsedID(ctx, line.ID) {
+		return sdkErr.Wrapf(types.ErrDuplicateID, "cannot open a credit line with an already used ID %s", line.ID)
+	}
+
+	// lines that do not specify interest are interest-free
+	if line.Interest.Rate.IsNil() {
+		line.Interest = types.NoInterest()
+	}
+
+	if err := keeper.applyCreditLineParams(ctx, line); err != nil {
+		return err
+	}
+
+	if line.Escrowed {
+		limit := sdk.NewCoins(sdk.NewCoin(line.Denom, line.Limit))
+		if err := keeper.supplyKeeper.SendCoinsFromAccountToModule(ctx, line.Lender, types.ModuleName, limit); err != nil {
+			return err
+		}
+	}
+
+	line.Drawn = sdk.ZeroInt()
+	line.AccruedInterest = sdk.ZeroInt()
+	line.InterestRemainder = sdk.ZeroDec()
+	line.LastAccrualHeight = ctx.BlockHeight()
+	line.LastAccrualTime = ctx.BlockTime()
+	keeper.SetCreditLine(ctx, line)
+
+	keeper.emitCreditLineEvent(ctx, types.ActionOpenLine, line, sdk.NewCoin(line.Denom, line.Limit).String())
+	return nil
+}
+
+// DrawCredit moves an amount of a credit line to its borrower, from the module
+// account for escrowed lines and from the lender otherwise. What is drawn never
+// exceeds the limit of the line
+func (keeper Keeper) DrawCredit(ctx sdk.Context, msg types.MsgDrawCredit) error {
+	line, err := keeper.GetCreditLine(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Borrower.Equals(line.Borrower) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the credit line with ID %s is not yours", msg.ID)
+	}
+
+	if msg.Amount.Denom != line.Denom {
+		return sdkErr.Wrapf(types.ErrDenomMismatch, "the credit line with ID %s is in %s, not %s", msg.ID, line.Denom, msg.Amount.Denom)
+	}
+
+	if line.IsExpired(ctx.BlockTime()) {
+		return sdkErr.Wrapf(types.ErrCreditLineExpired, "the credit line with ID %s expired at %s", msg.ID, line.ExpiryTime)
+	}
+
+	if msg.Amount.Amount.GT(line.Available().Amount) {
+		return sdkErr.Wrapf(types.ErrCreditLimitExceeded, "only %s can be drawn from the credit line with ID %s", line.Available(), msg.ID)
+	}
+
+	amount := sdk.NewCoins(msg.Amount)
+	if line.Escrowed {
+		err = keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, line.Borrower, amount)
+	} else {
+		err = keeper.bankKeeper.SendCoins(ctx, line.Lender, line.Borrower, amount)
+	}
+	if err != nil {
+		return err
+	}
+
+	line.Drawn = line.Drawn.Add(msg.Amount.Amount)
+	keeper.SetCreditLine(ctx, line)
+
+	keeper.emitCreditLineEvent(ctx, types.ActionDraw, line, msg.Amount.String())
+	return nil
+}
+
+// RepayCredit pays back an amount of a credit line, never more than what is owed.
+// Payments cover the accrued interest first, that goes to the lender, and then what
+// is drawn, that goes back to the module account for escrowed lines, so that it can
+// be drawn again, and to the lender otherwise
+func (keeper Keeper) RepayCredit(ctx sdk.Context, msg types.MsgRepayCredit) error {
+	line, err := keeper.GetCreditLine(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Borrower.Equals(line.Borrower) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the credit line with ID %s is not yours", msg.ID)
+	}
+
+	payment := msg.Amount
+	if msg.InFull {
+		payment = line.Owed()
+	}
+
+	if payment.Denom != line.Denom {
+		return sdkErr.Wrapf(types.ErrDenomMismatch, "the credit line with ID %s is in %s, not %s", msg.ID, line.Denom, payment.Denom)
+	}
+
+	// the borrower never pays more than what is owed
+	payment.Amount = sdk.MinInt(payment.Amount, line.Owed().Amount)
+
+	interest := sdk.NewCoin(line.Denom, sdk.MinInt(payment.Amount, line.AccruedInterest))
+	principal := payment.Sub(interest)
+
+	if err := keeper.bankKeeper.SendCoins(ctx, line.Borrower, line.Lender, sdk.NewCoins(interest)); err != nil {
+		return err
+	}
+
+	if line.Escrowed {
+		err = keeper.supplyKeeper.SendCoinsFromAccountToModule(ctx, line.Borrower, types.ModuleName, sdk.NewCoins(principal))
+	} else {
+		err = keeper.bankKeeper.SendCoins(ctx, line.Borrower, line.Lender, sdk.NewCoins(principal))
+	}
+	if err != nil {
+		return err
+	}
+
+	line.AccruedInterest = line.AccruedInterest.Sub(interest.Amount)
+	line.Drawn = line.Drawn.Sub(principal.Amount)
+	keeper.SetCreditLine(ctx, line)
+
+	keeper.emitCreditLineEvent(ctx, types.ActionRepay, line, payment.String())
+	return nil
+}
+
+// CloseCreditLine lets the lender or the borrower of a credit line close it, once
+// nothing is owed on it anymore. The limit of an escrowed line goes back to the lender
+func (keeper Keeper) CloseCreditLine(ctx sdk.Context, msg types.MsgCloseCreditLine) error {
+	line, err := keeper.GetCreditLine(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Sender.Equals(line.Lender) && !msg.Sender.Equals(line.Borrower) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "you are not a party of the credit line with ID %s", msg.ID)
+	}
+
+	if line.Owed().IsPositive() {
+		return sdkErr.Wrapf(types.ErrCreditLineInUse, "%s is still owed on the credit line with ID %s", line.Owed(), msg.ID)
+	}
+
+	if line.Escrowed {
+		limit := sdk.NewCoins(sdk.NewCoin(line.Denom, line.Limit))
+		if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, line.Lender, limit); err != nil {
+			return err
+		}
+	}
+
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(getCreditLineStoreKey(line.ID))
+	keeper.deleteCreditLineIndexes(ctx, line)
+
+	keeper.emitCreditLineEvent(ctx, types.ActionCloseLine, line, "")
+	return nil
+}
+
+// AccrueCreditLineInterest updates the accrued interest of all credit lines
+// whose accrual period has elapsed since their last accrual. Only the lines
+// due in the accrual indexes are loaded
+func (keeper Keeper) AccrueCreditLineInterest(ctx sdk.Context) {
+	ids := append(keeper.getDueHeightIDs(ctx, lineAccrualHeightIndexPrefix, ctx.BlockHeight()),
+		keeper.getDueTimeIDs(ctx, lineAccrualTimeIndexPrefix, ctx.BlockTime().Add(time.Nanosecond))...)
+
+	for _, id := range ids {
+		line, err := keeper.GetCreditLine(ctx, id)
+		if err != nil {
+			panic(err)
+		}
+
+		if accrued, ok := accrueCreditLineInterest(line, ctx.BlockHeight(), ctx.BlockTime()); ok {
+			keeper.SetCreditLine(ctx, accrued)
+		}
+	}
+}
+
+// accrueCreditLineInterest yields the line with the interest of all the periods elapsed
+// up to the given height and time, and true if some interest period has elapsed.
+// Interest accrues on what is drawn, and also on the accrued interest if compound.
+// As for debts, the fraction of coin is carried to the next accrual, and a line
+// whose interest overflows is left as it is
+func accrueCreditLineInterest(line types.CreditLine, height int64, blockTime time.Time) (accrued types.CreditLine, ok bool) {
+	terms := line.Interest
+	if terms.IsZero() {
+		return line, false
+	}
+
+	periods := elapsedPeriods(terms, line.LastAccrualHeight, line.LastAccrualTime, height, blockTime)
+	if periods <= 0 {
+		return line, false
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			accrued, ok = line, false
+		}
+	}()
+
+	carried := line.InterestRemainder
+	if carried.IsNil() {
+		carried = sdk.ZeroDec()
+	}
+	exact := terms.ExactInterestFor(line.Drawn, line.AccruedInterest.ToDec().Add(carried), periods).Add(carried)
+	whole := exact.TruncateInt()
+
+	accrued = line
+	accrued.AccruedInterest = line.AccruedInterest.Add(whole)
+	accrued.InterestRemainder = exact.Sub(whole.ToDec())
+	accrued.LastAccrualHeight, accrued.LastAccrualTime = advanceAccrual(terms, line.LastAccrualHeight, line.LastAccrualTime, periods)
+	return accrued, true
+}
+
+func (keeper Keeper) creditLinesIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(keeper.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(creditLineStorePrefix))
+}
+
+// a functional type used to filter credit lines
+type creditLineDiscrimination func(line types.CreditLine) bool
+
+func (keeper Keeper) getCreditLines(ctx sdk.Context, logic creditLineDiscrimination) []types.CreditLine {
+	ri := keeper.creditLinesIterator(ctx)
+	defer ri.Close()
+
+	receivedResult := []types.CreditLine{}
+	for ; ri.Valid(); ri.Next() {
+		var line types.CreditLine
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &line)
+
+		// we only accept credit lines that satisfy the filter
+		if logic(line) {
+			receivedResult = append(receivedResult, line)
+		}
+	}
+
+	return receivedResult
+}
+
+func (keeper Keeper) GetAllCreditLines(ctx sdk.Context) []types.CreditLine {
+	// we use a filter that accepts everything
+	return keeper.getCreditLines(ctx, func(_ types.CreditLine) bool {
+		return true
+	})
+}
+
+func (keeper Keeper) GetBorrowerCreditLines(ctx sdk.Context, address sdk.AccAddress) []types.CreditLine {
+	// we use a filter that projects on the borrower
+	return keeper.getCreditLines(ctx, func(line types.CreditLine) bool {
+		return line.Borrower.Equals(address)
+	})
+}
+
+func (keeper Keeper) GetLenderCreditLines(ctx sdk.Context, address sdk.AccAddress) []types.CreditLine {
+	// we use a filter that projects on the lender
+	return keeper.getCreditLines(ctx, func(line types.CreditLine) bool {
+		return line.Lender.Equals(address)
+	})
+}
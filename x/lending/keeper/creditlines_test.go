@@ -0,0 +1,243 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_DrawAndRepayCredit(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	moduleAddress := supply.NewModuleAddress(types.ModuleName)
+
+	foo := func(amount int64) sdk.Coins {
+		return sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(amount)))
+	}
+
+	tests := []struct {
+		name           string
+		escrowed       bool
+		lenderAfter    sdk.Coins
+		moduleAfter    sdk.Coins
+		lenderRepaid   sdk.Coins
+		moduleRepaid   sdk.Coins
+		lenderAtClose  sdk.Coins
+		expectedLocked sdk.Coins
+	}{
+		{"draws from the lender", false, foo(700), nil, foo(1000), nil, foo(1000), nil},
+		{"draws from the escrow", true, foo(0), foo(700), foo(0), foo(1000), foo(1000), foo(700)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			require.NoError(t, bankKeeper.SetCoins(ctx, lender, foo(1000)))
+
+			line := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+			line.Escrowed = tt.escrowed
+			require.NoError(t, keeper.OpenCreditLine(ctx, line))
+
+			require.NoError(t, keeper.DrawCredit(ctx, types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(300)), borrower)))
+			require.Equal(t, foo(300), bankKeeper.GetCoins(ctx, borrower))
+			require.Equal(t, tt.lenderAfter.String(), bankKeeper.GetCoins(ctx, lender).String())
+			require.Equal(t, tt.moduleAfter.String(), bankKeeper.GetCoins(ctx, moduleAddress).String())
+			require.Equal(t, tt.expectedLocked.String(), keeper.GetLockedCoins(ctx).String())
+
+			// utilization can never exceed the limit
+			err := keeper.DrawCredit(ctx, types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(701)), borrower))
+			require.True(t, errors.Is(err, types.ErrCreditLimitExceeded), err)
+
+			newLine, err := keeper.GetCreditLine(ctx, "L1")
+			require.NoError(t, err)
+			require.Equal(t, sdk.NewInt(300), newLine.Drawn)
+			require.Equal(t, sdk.NewCoin("foo", sdk.NewInt(700)), newLine.Available())
+			require.Equal(t, sdk.NewDecWithPrec(3, 1), newLine.Utilization())
+
+			// the line cannot be closed while something is drawn
+			err = keeper.CloseCreditLine(ctx, types.NewMsgCloseCreditLine("L1", lender))
+			require.True(t, errors.Is(err, types.ErrCreditLineInUse), err)
+
+			require.NoError(t, keeper.RepayCredit(ctx, types.NewMsgRepayCreditInFull("L1", borrower)))
+			require.True(t, bankKeeper.GetCoins(ctx, borrower).IsZero())
+			require.Equal(t, tt.lenderRepaid.String(), bankKeeper.GetCoins(ctx, lender).String())
+			require.Equal(t, tt.moduleRepaid.String(), bankKeeper.GetCoins(ctx, moduleAddress).String())
+
+			require.NoError(t, keeper.CloseCreditLine(ctx, types.NewMsgCloseCreditLine("L1", borrower)))
+			require.Equal(t, tt.lenderAtClose.String(), bankKeeper.GetCoins(ctx, lender).String())
+			require.True(t, bankKeeper.GetCoins(ctx, moduleAddress).IsZero())
+			require.Empty(t, keeper.GetAllCreditLines(ctx))
+
+			_, err = keeper.GetCreditLine(ctx, "L1")
+			require.True(t, errors.Is(err, types.ErrCreditLineNotFound), err)
+		})
+	}
+}
+
+func TestKeeper_DrawCreditErrors(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	expiry := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		msg     types.MsgDrawCredit
+		wantErr error
+	}{
+		{"draw within the limit", types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(1000)), borrower), nil},
+		{"draw beyond the limit", types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(1001)), borrower), types.ErrCreditLimitExceeded},
+		{"draw in another denom", types.NewMsgDrawCredit("L1", sdk.NewCoin("bar", sdk.NewInt(10)), borrower), types.ErrDenomMismatch},
+		{"draw by someone else", types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(10)), lender), types.ErrUnauthorized},
+		{"draw from an unknown line", types.NewMsgDrawCredit("L2", sdk.NewCoin("foo", sdk.NewInt(10)), borrower), types.ErrCreditLineNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			ctx = ctx.WithBlockTime(expiry.Add(-time.Hour))
+			require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(5000)))))
+
+			line := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+			line.ExpiryTime = expiry
+			require.NoError(t, keeper.OpenCreditLine(ctx, line))
+
+			err := keeper.DrawCredit(ctx, tt.msg)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			// nothing can be drawn once the line expires, but it can still be repaid
+			ctx = ctx.WithBlockTime(expiry.Add(time.Hour))
+			require.NoError(t, keeper.RepayCredit(ctx, types.NewMsgRepayCredit("L1", sdk.NewCoin("foo", sdk.NewInt(500)), borrower)))
+			err = keeper.DrawCredit(ctx, types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(10)), borrower))
+			require.True(t, errors.Is(err, types.ErrCreditLineExpired), err)
+		})
+	}
+}
+
+func TestKeeper_OpenCreditLineErrors(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	params := types.DefaultParams()
+	params.MaxInterestRate = sdk.NewDecWithPrec(1, 1)
+
+	escrowed := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(2000)))
+	escrowed.Escrowed = true
+
+	tooExpensive := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+	tooExpensive.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(2, 1), types.InterestSimple, 10, types.PeriodBlocks)
+
+	tests := []struct {
+		name    string
+		line    types.CreditLine
+		wantErr error
+	}{
+		{"line within the rules", types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(2000))), nil},
+		{"ID of a debt", types.NewCreditLine("A1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000))), types.ErrDuplicateID},
+		{"escrow beyond the funds of the lender", escrowed, sdkErr.ErrInsufficientFunds},
+		{"interest rate over the maximum", tooExpensive, types.ErrInterestRateTooHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			keeper.SetParams(ctx, params)
+			require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+			require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A1", borrower, sdk.NewCoin("foo", sdk.NewInt(10)), lender)))
+
+			err := keeper.OpenCreditLine(ctx, tt.line)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				require.Empty(t, keeper.GetAllCreditLines(ctx))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, keeper.GetBorrowerCreditLines(ctx, borrower), 1)
+			require.Len(t, keeper.GetLenderCreditLines(ctx, lender), 1)
+			require.Empty(t, keeper.GetLenderCreditLines(ctx, borrower))
+		})
+	}
+}
+
+func TestKeeper_AccrueCreditLineInterest(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(1)
+	params := types.DefaultParams()
+	params.BlocksPerYear = 100 // a year of ten interest periods, within the max annual yield
+	keeper.SetParams(ctx, params)
+	require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	line := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+	line.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+	require.NoError(t, keeper.OpenCreditLine(ctx, line))
+	require.NoError(t, keeper.DrawCredit(ctx, types.NewMsgDrawCredit("L1", sdk.NewCoin("foo", sdk.NewInt(500)), borrower)))
+
+	// interest accrues on what is drawn, once per elapsed period
+	keeper.AccrueCreditLineInterest(ctx.WithBlockHeight(10))
+	keeper.AccrueCreditLineInterest(ctx.WithBlockHeight(21))
+
+	newLine, err := keeper.GetCreditLine(ctx, "L1")
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt(10), newLine.AccruedInterest)
+	require.Equal(t, int64(21), newLine.LastAccrualHeight)
+	require.Equal(t, sdk.NewCoin("foo", sdk.NewInt(510)), newLine.Owed())
+
+	// repayments cover the interest first, that goes to the lender
+	require.NoError(t, keeper.RepayCredit(ctx, types.NewMsgRepayCredit("L1", sdk.NewCoin("foo", sdk.NewInt(110)), borrower)))
+
+	newLine, err = keeper.GetCreditLine(ctx, "L1")
+	require.NoError(t, err)
+	require.True(t, newLine.AccruedInterest.IsZero())
+	require.Equal(t, sdk.NewInt(400), newLine.Drawn)
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(610))), bankKeeper.GetCoins(ctx, lender))
+
+	// the 50 coins left drawn accrue half a coin per period, that is carried to the next period
+	require.NoError(t, keeper.RepayCredit(ctx, types.NewMsgRepayCredit("L1", sdk.NewCoin("foo", sdk.NewInt(350)), borrower)))
+	keeper.AccrueCreditLineInterest(ctx.WithBlockHeight(31))
+
+	newLine, err = keeper.GetCreditLine(ctx, "L1")
+	require.NoError(t, err)
+	require.True(t, newLine.AccruedInterest.IsZero())
+	require.Equal(t, sdk.NewDecWithPrec(5, 1), newLine.InterestRemainder)
+
+	keeper.AccrueCreditLineInterest(ctx.WithBlockHeight(41))
+
+	newLine, err = keeper.GetCreditLine(ctx, "L1")
+	require.NoError(t, err)
+	require.Equal(t, sdk.OneInt(), newLine.AccruedInterest)
+	require.True(t, newLine.InterestRemainder.IsZero())
+}
+
+func TestKeeper_OpenCreditLineResetsRemainder(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(1)
+
+	// a remainder set by the lender would be owed by a borrower that never drew
+	line := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+	line.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 8), types.InterestSimple, 1, types.PeriodBlocks)
+	line.InterestRemainder = sdk.NewDec(500)
+	require.Error(t, types.NewMsgOpenCreditLine(line).ValidateBasic())
+	require.NoError(t, keeper.OpenCreditLine(ctx, line))
+
+	keeper.AccrueCreditLineInterest(ctx.WithBlockHeight(2))
+
+	newLine, err := keeper.GetCreditLine(ctx, "L1")
+	require.NoError(t, err)
+	require.True(t, newLine.AccruedInterest.IsZero())
+	require.True(t, newLine.InterestRemainder.IsZero())
+}
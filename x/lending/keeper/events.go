@@ -0,0 +1,58 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// emitDebtEvent reports a transition of the debt, that moved the given amount,
+// if any, and left it owing what remains. Extra attributes follow the common ones
+func (keeper Keeper) emitDebtEvent(ctx sdk.Context, action string, debt types.Debt, amount string, extra ...sdk.Attribute) {
+	attributes := []sdk.Attribute{
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+		sdk.NewAttribute(types.AttributeKeyAction, action),
+		sdk.NewAttribute(types.AttributeKeyDebtID, debt.ID),
+		sdk.NewAttribute(types.AttributeKeyDebtor, debt.Debtor.String()),
+		sdk.NewAttribute(types.AttributeKeyCreditor, debt.Creditor.String()),
+	}
+	if amount != "" {
+		attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyAmount, amount))
+	}
+	attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyRemaining, debt.Owed().String()))
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeDebt, append(attributes, extra...)...))
+}
+
+// emitCreditLineEvent reports a transition of the credit line, that moved the
+// given amount, if any, and left it owing what remains
+func (keeper Keeper) emitCreditLineEvent(ctx sdk.Context, action string, line types.CreditLine, amount string) {
+	attributes := []sdk.Attribute{
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+		sdk.NewAttribute(types.AttributeKeyAction, action),
+		sdk.NewAttribute(types.AttributeKeyLineID, line.ID),
+		sdk.NewAttribute(types.AttributeKeyLender, line.Lender.String()),
+		sdk.NewAttribute(types.AttributeKeyBorrower, line.Borrower.String()),
+	}
+	if amount != "" {
+		attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyAmount, amount))
+	}
+	attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyRemaining, line.Owed().String()))
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeCreditLine, attributes...))
+}
+
+// emitPoolEvent reports a movement of the pool of the given amount, if any,
+// with extra attributes such as the depositor and the shares minted or burnt
+func (keeper Keeper) emitPoolEvent(ctx sdk.Context, action string, pool types.Pool, amount string, extra ...sdk.Attribute) {
+	attributes := []sdk.Attribute{
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.AttributeValueCategory),
+		sdk.NewAttribute(types.AttributeKeyAction, action),
+		sdk.NewAttribute(types.AttributeKeyDenom, pool.Denom),
+	}
+	if amount != "" {
+		attributes = append(attributes, sdk.NewAttribute(types.AttributeKeyAmount, amount))
+	}
+	attributes = append(attributes, extra...)
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypePool, attributes...))
+}
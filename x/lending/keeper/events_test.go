@@ -0,0 +1,91 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+// debtActions yields the actions of the debt events emitted so far
+func debtActions(ctx sdk.Context) []string {
+	var actions []string
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type != types.EventTypeDebt {
+			continue
+		}
+
+		for _, attribute := range event.Attributes {
+			if string(attribute.Key) == types.AttributeKeyAction {
+				actions = append(actions, string(attribute.Value))
+			}
+		}
+	}
+
+	return actions
+}
+
+// debtAttributes yields the attributes of the last debt event emitted so far
+func debtAttributes(ctx sdk.Context) map[string]string {
+	attributes := make(map[string]string)
+	for _, event := range ctx.EventManager().Events() {
+		if event.Type == types.EventTypeDebt {
+			for _, attribute := range event.Attributes {
+				attributes[string(attribute.Key)] = string(attribute.Value)
+			}
+		}
+	}
+
+	return attributes
+}
+
+func TestKeeper_DebtEvents(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(3000)))))
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, keeper.DisburseDebt(ctx, types.NewDebt("A1", debtor, amount, creditor)))
+	require.Equal(t, []string{types.ActionCreate}, debtActions(ctx))
+	require.Equal(t, map[string]string{
+		sdk.AttributeKeyModule:      types.ModuleName,
+		types.AttributeKeyAction:    types.ActionCreate,
+		types.AttributeKeyDebtID:    "A1",
+		types.AttributeKeyDebtor:    debtor.String(),
+		types.AttributeKeyCreditor:  creditor.String(),
+		types.AttributeKeyAmount:    "1000foo",
+		types.AttributeKeyRemaining: "1000foo",
+	}, debtAttributes(ctx))
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(300)), debtor)))
+	require.Equal(t, []string{types.ActionPay}, debtActions(ctx))
+	require.Equal(t, "300foo", debtAttributes(ctx)[types.AttributeKeyAmount])
+	require.Equal(t, "700foo", debtAttributes(ctx)[types.AttributeKeyRemaining])
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(200)), creditor)))
+	require.Equal(t, []string{types.ActionChange}, debtActions(ctx))
+	require.Equal(t, "500foo", debtAttributes(ctx)[types.AttributeKeyRemaining])
+
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, keeper.ProposeDebt(ctx, types.NewDebt("P1", debtor, amount, creditor), 0))
+	require.NoError(t, keeper.AcceptDebt(ctx, types.NewMsgAcceptDebt("P1", debtor)))
+	require.NoError(t, keeper.ProposeDebt(ctx, types.NewDebt("P2", debtor, amount, creditor), 0))
+	require.NoError(t, keeper.RejectDebt(ctx, types.NewMsgRejectDebt("P2", debtor)))
+	require.NoError(t, keeper.ProposeDebt(ctx, types.NewDebt("P3", debtor, amount, creditor), 0))
+	require.NoError(t, keeper.WithdrawProposal(ctx, types.NewMsgWithdrawProposal("P3", creditor)))
+	require.NoError(t, keeper.ProposeDebt(ctx, types.NewDebt("P4", debtor, amount, creditor), 1))
+	keeper.RemoveExpiredProposals(ctx.WithBlockHeight(2))
+	require.Equal(t, []string{
+		types.ActionPropose, types.ActionAccept,
+		types.ActionPropose, types.ActionReject,
+		types.ActionPropose, types.ActionWithdraw,
+		types.ActionPropose, types.ActionExpire,
+	}, debtActions(ctx))
+}
@@ -0,0 +1,20 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// chargeLateFee adds to the accrued fees of the debt its late fee for missing a due
+// date with the given amount overdue, capped by the params, and records it in the
+// ledger of the debt. It yields the fee, that is zero for debts without late fees
+func (keeper Keeper) chargeLateFee(ctx sdk.Context, debt *types.Debt, overdue types.DebtCoins) types.DebtCoins {
+	fee := debt.LateFees.FeeFor(overdue, keeper.GetParams(ctx).MaxLateFeeRate)
+	if fee.IsZero() {
+		return fee
+	}
+
+	debt.AccruedFees = debt.AccruedFees.Add(fee)
+	keeper.recordEntry(ctx, types.EntryFee, *debt, fee)
+	return fee
+}
@@ -0,0 +1,220 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_ChargeLateFees(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	maturity := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		lateFees        types.LateFeeTerms
+		expectedFee     int64
+		expectedActions []string
+	}{
+		{
+			"debt without late fees",
+			types.NoLateFees(),
+			0,
+			[]string{types.ActionOverdue},
+		},
+		{
+			"flat fee",
+			types.NewLateFeeTerms(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10))), sdk.ZeroDec(), sdk.ZeroDec()),
+			10,
+			[]string{types.ActionOverdue, types.ActionLateFee},
+		},
+		{
+			"share of the amount overdue",
+			types.NewLateFeeTerms(nil, sdk.NewDecWithPrec(5, 2), sdk.ZeroDec()),
+			50,
+			[]string{types.ActionOverdue, types.ActionLateFee},
+		},
+		{
+			"fee capped by the params",
+			types.NewLateFeeTerms(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(50))), sdk.NewDecWithPrec(1, 1), sdk.ZeroDec()),
+			100,
+			[]string{types.ActionOverdue, types.ActionLateFee},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+
+			debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+			debt.MaturityTime = maturity
+			debt.GracePeriod = 24 * time.Hour
+			debt.LateFees = tt.lateFees
+			require.NoError(t, keeper.activateDebt(ctx, debt))
+
+			ctx = ctx.WithBlockTime(maturity.Add(time.Hour)).WithEventManager(sdk.NewEventManager())
+			keeper.UpdateDebtStatuses(ctx)
+			require.Equal(t, tt.expectedActions, debtActions(ctx))
+
+			newDebt, err := keeper.GetDebt(ctx, "A1")
+			require.NoError(t, err)
+			fee := types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(tt.expectedFee)))
+			require.Equal(t, fee, newDebt.AccruedFees)
+			require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(1000+tt.expectedFee))), newDebt.Owed())
+
+			ledger := keeper.GetLedger(ctx, "A1")
+			if tt.expectedFee == 0 {
+				require.Empty(t, ledger)
+				return
+			}
+
+			require.Len(t, ledger, 1)
+			require.Equal(t, types.EntryFee, ledger[0].Type)
+			require.Equal(t, fee, ledger[0].Amount)
+
+			// the fee is charged once, when the debt misses its maturity
+			ctx = ctx.WithBlockTime(maturity.Add(48 * time.Hour)).WithEventManager(sdk.NewEventManager())
+			keeper.UpdateDebtStatuses(ctx)
+			require.Equal(t, []string{types.ActionDefault}, debtActions(ctx))
+
+			newDebt, err = keeper.GetDebt(ctx, "A1")
+			require.NoError(t, err)
+			require.Equal(t, fee, newDebt.AccruedFees)
+		})
+	}
+}
+
+func TestKeeper_ChargeLateFeesOnInstallments(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Repayment = types.NewScheduleTerms(types.ScheduleEqual, 4, time.Hour)
+	debt.LateFees = types.NewLateFeeTerms(nil, sdk.NewDecWithPrec(1, 1), sdk.ZeroDec())
+	require.NoError(t, keeper.activateDebt(ctx.WithBlockTime(start), debt))
+
+	ctx = ctx.WithBlockTime(start.Add(2*time.Hour + time.Minute)).WithEventManager(sdk.NewEventManager())
+	keeper.UpdateInstallmentStatuses(ctx)
+	require.Equal(t, []string{
+		types.ActionInstallmentLate, types.ActionLateFee,
+		types.ActionInstallmentLate, types.ActionLateFee,
+	}, debtActions(ctx))
+	require.Equal(t, "2", debtAttributes(ctx)[types.AttributeKeyInstallment])
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(50))), newDebt.AccruedFees)
+	require.Len(t, keeper.GetLedger(ctx, "A1"), 2)
+}
+
+func TestKeeper_AccruePenaltyInterest(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 3600, types.PeriodSeconds)
+	debt.MaturityTime = start.Add(time.Hour)
+	debt.GracePeriod = 24 * time.Hour
+	debt.LateFees = types.NewLateFeeTerms(nil, sdk.ZeroDec(), sdk.NewDecWithPrec(5, 2))
+	require.NoError(t, keeper.activateDebt(ctx.WithBlockTime(start), debt))
+
+	// interest accrues at its normal rate until the debt is late
+	ctx = ctx.WithBlockTime(start.Add(time.Hour))
+	keeper.AccrueInterest(ctx)
+	keeper.UpdateDebtStatuses(ctx)
+
+	ctx = ctx.WithBlockTime(start.Add(2 * time.Hour))
+	keeper.UpdateDebtStatuses(ctx)
+	keeper.AccrueInterest(ctx)
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.StatusOverdue, newDebt.Status)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(60))), newDebt.AccruedInterest)
+
+	ledger := keeper.GetLedger(ctx, "A1")
+	require.Len(t, ledger, 2)
+	require.Equal(t, types.EntryInterest, ledger[0].Type)
+	require.Equal(t, types.EntryPenalty, ledger[1].Type)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(50))), ledger[1].Amount)
+}
+
+func TestKeeper_PayDebtCoversFeesFirst(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	maturity := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.MaturityTime = maturity
+	debt.GracePeriod = 24 * time.Hour
+	debt.LateFees = types.NewLateFeeTerms(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10))), sdk.ZeroDec(), sdk.ZeroDec())
+	require.NoError(t, keeper.activateDebt(ctx, debt))
+
+	ctx = ctx.WithBlockTime(maturity.Add(time.Hour))
+	keeper.UpdateDebtStatuses(ctx)
+
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(15)), debtor)))
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedFees.IsZero())
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(995))), newDebt.Amount)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(10))), newDebt.Settlement.FeesPaid)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(5))), newDebt.Settlement.PrincipalPaid)
+	require.Equal(t, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(15))), bankKeeper.GetCoins(ctx, creditor))
+}
+
+func TestKeeper_LateFeeParamsAreEnforced(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	params := types.DefaultParams()
+	params.MaxLateFeeRate = sdk.NewDecWithPrec(1, 1)
+	params.MaxPenaltyRate = sdk.NewDecWithPrec(2, 1)
+
+	tests := []struct {
+		name     string
+		lateFees types.LateFeeTerms
+		wantErr  error
+	}{
+		{"late fees within the rules", types.NewLateFeeTerms(nil, sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(2, 1)), nil},
+		{"late fee rate over the maximum", types.NewLateFeeTerms(nil, sdk.NewDecWithPrec(2, 1), sdk.ZeroDec()), types.ErrLateFeeTooHigh},
+		{"penalty rate over the maximum", types.NewLateFeeTerms(nil, sdk.ZeroDec(), sdk.NewDecWithPrec(3, 1)), types.ErrInterestRateTooHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			keeper.SetParams(ctx, params)
+			require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+			debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+			debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, types.SecondsPerYear, types.PeriodSeconds)
+			debt.LateFees = tt.lateFees
+
+			err := keeper.ProposeDebt(ctx, debt, 10)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
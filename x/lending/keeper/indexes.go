@@ -0,0 +1,234 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// the secondary indexes map debtor|id and creditor|id to nothing, so that
+// the debts of an address can be found with a prefix scan
+const (
+	debtorIndexPrefix   = ":debtor:"
+	creditorIndexPrefix = ":creditor:"
+)
+
+// the due indexes map the height or time when the block hooks must next look at
+// a debt or a credit line, followed by its ID, to nothing, so that each block
+// only loads what is due instead of all the debts and credit lines: the end of
+// their current interest period, in blocks or in seconds, the time after which
+// the status of a debt changes and the due time of its next pending installment
+const (
+	accrualHeightIndexPrefix     = ":accrualheight:"
+	accrualTimeIndexPrefix       = ":accrualtime:"
+	statusTimeIndexPrefix        = ":statustime:"
+	installmentTimeIndexPrefix   = ":installmenttime:"
+	lineAccrualHeightIndexPrefix = ":lineaccrualheight:"
+	lineAccrualTimeIndexPrefix   = ":lineaccrualtime:"
+)
+
+// debtIndexesVersion is increased whenever the layout of the indexes changes,
+// so that they are rebuilt from the debts and the credit lines in the store
+const debtIndexesVersion uint64 = 2
+
+var debtIndexesVersionKey = []byte(":debtindexesversion:")
+
+func getDebtorIndexPrefix(debtor sdk.AccAddress) []byte {
+	return []byte(debtorIndexPrefix + debtor.String() + "|")
+}
+
+func getCreditorIndexPrefix(creditor sdk.AccAddress) []byte {
+	return []byte(creditorIndexPrefix + creditor.String() + "|")
+}
+
+// getHeightIndexKey yields the key of the ID in the index under the given prefix, at the given height
+func getHeightIndexKey(prefix string, height int64, id string) []byte {
+	if height < 0 {
+		height = 0
+	}
+
+	return append(append([]byte(prefix), sdk.Uint64ToBigEndian(uint64(height))...), id...)
+}
+
+// getTimeIndexKey yields the key of the ID in the index under the given prefix, at the given time
+func getTimeIndexKey(prefix string, at time.Time, id string) []byte {
+	return append(append([]byte(prefix), sdk.FormatTimeBytes(at)...), id...)
+}
+
+// getAccrualIndexKey yields the key of the ID in the accrual index of heights or of times,
+// depending on the period unit of the terms, at the end of their current period.
+// It yields nil if nothing accrues with the terms
+func getAccrualIndexKey(heightPrefix, timePrefix string, terms types.InterestTerms, lastHeight int64,
+	lastTime time.Time, id string) []byte {
+	if terms.IsZero() || terms.Period <= 0 {
+		return nil
+	}
+
+	if terms.PeriodUnit == types.PeriodSeconds {
+		return getTimeIndexKey(timePrefix, lastTime.Add(time.Duration(terms.Period)*time.Second), id)
+	}
+
+	return getHeightIndexKey(heightPrefix, lastHeight+terms.Period, id)
+}
+
+// getDueIndexKeys yields the keys of the debt in the due indexes
+func getDueIndexKeys(debt types.Debt) [][]byte {
+	var keys [][]byte
+	accrual := getAccrualIndexKey(accrualHeightIndexPrefix, accrualTimeIndexPrefix, debt.CurrentInterest(),
+		debt.LastAccrualHeight, debt.LastAccrualTime, debt.ID)
+	if accrual != nil {
+		keys = append(keys, accrual)
+	}
+
+	if next, ok := debt.NextStatusTime(); ok {
+		keys = append(keys, getTimeIndexKey(statusTimeIndexPrefix, next, debt.ID))
+	}
+
+	if next, ok := debt.Schedule.NextDueTime(); ok && !debt.Status.IsClosed() {
+		keys = append(keys, getTimeIndexKey(installmentTimeIndexPrefix, next, debt.ID))
+	}
+
+	return keys
+}
+
+func (keeper Keeper) setDebtIndexes(ctx sdk.Context, debt types.Debt) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(append(getDebtorIndexPrefix(debt.Debtor), debt.ID...), []byte{})
+	store.Set(append(getCreditorIndexPrefix(debt.Creditor), debt.ID...), []byte{})
+	for _, key := range getDueIndexKeys(debt) {
+		store.Set(key, []byte{})
+	}
+}
+
+func (keeper Keeper) deleteDebtIndexes(ctx sdk.Context, debt types.Debt) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(append(getDebtorIndexPrefix(debt.Debtor), debt.ID...))
+	store.Delete(append(getCreditorIndexPrefix(debt.Creditor), debt.ID...))
+	for _, key := range getDueIndexKeys(debt) {
+		store.Delete(key)
+	}
+}
+
+// getCreditLineAccrualIndexKey yields the key of the line in the accrual index, or nil if it is interest-free
+func getCreditLineAccrualIndexKey(line types.CreditLine) []byte {
+	return getAccrualIndexKey(lineAccrualHeightIndexPrefix, lineAccrualTimeIndexPrefix, line.Interest,
+		line.LastAccrualHeight, line.LastAccrualTime, line.ID)
+}
+
+func (keeper Keeper) setCreditLineIndexes(ctx sdk.Context, line types.CreditLine) {
+	if key := getCreditLineAccrualIndexKey(line); key != nil {
+		ctx.KVStore(keeper.storeKey).Set(key, []byte{})
+	}
+}
+
+func (keeper Keeper) deleteCreditLineIndexes(ctx sdk.Context, line types.CreditLine) {
+	if key := getCreditLineAccrualIndexKey(line); key != nil {
+		ctx.KVStore(keeper.storeKey).Delete(key)
+	}
+}
+
+// getDueIDs yields the IDs in the due index under the given prefix, whose keys sort
+// before the given end, in their order. The width is that of the heights or times
+// in the keys. The IDs are collected before the caller updates the index
+func (keeper Keeper) getDueIDs(ctx sdk.Context, prefix string, end []byte, width int) []string {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := store.Iterator([]byte(prefix), end)
+	defer ri.Close()
+
+	var ids []string
+	for ; ri.Valid(); ri.Next() {
+		ids = append(ids, string(ri.Key()[len(prefix)+width:]))
+	}
+
+	return ids
+}
+
+// getDueHeightIDs yields the IDs in the index of heights under the given prefix, up to the given height
+func (keeper Keeper) getDueHeightIDs(ctx sdk.Context, prefix string, height int64) []string {
+	return keeper.getDueIDs(ctx, prefix, getHeightIndexKey(prefix, height+1, ""), len(sdk.Uint64ToBigEndian(0)))
+}
+
+// getDueTimeIDs yields the IDs in the index of times under the given prefix, strictly before the given time
+func (keeper Keeper) getDueTimeIDs(ctx sdk.Context, prefix string, before time.Time) []string {
+	return keeper.getDueIDs(ctx, prefix, getTimeIndexKey(prefix, before, ""), len(sdk.FormatTimeBytes(time.Time{})))
+}
+
+// getDueDebts yields the debts with the given IDs
+func (keeper Keeper) getDueDebts(ctx sdk.Context, ids []string) []types.Debt {
+	debts := make([]types.Debt, len(ids))
+	for i, id := range ids {
+		debt, err := keeper.GetDebt(ctx, id)
+		if err != nil {
+			panic(err)
+		}
+
+		debts[i] = debt
+	}
+
+	return debts
+}
+
+// getIndexedDebts yields the debts whose IDs are in the index under the given prefix
+func (keeper Keeper) getIndexedDebts(ctx sdk.Context, prefix []byte) []types.Debt {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, prefix)
+	defer ri.Close()
+
+	debts := []types.Debt{}
+	for ; ri.Valid(); ri.Next() {
+		id := string(ri.Key()[len(prefix):])
+
+		debt, err := keeper.GetDebt(ctx, id)
+		if err != nil {
+			panic(err)
+		}
+
+		debts = append(debts, debt)
+	}
+
+	return debts
+}
+
+// MigrateDebtIndexes rebuilds the secondary and due indexes of the debts and the credit
+// lines if the store was written by a version of the module with older or no indexes
+func (keeper Keeper) MigrateDebtIndexes(ctx sdk.Context) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	if store.Has(debtIndexesVersionKey) {
+		var version uint64
+		keeper.cdc.MustUnmarshalBinaryBare(store.Get(debtIndexesVersionKey), &version)
+		if version >= debtIndexesVersion {
+			return
+		}
+	}
+
+	for _, prefix := range []string{debtorIndexPrefix, creditorIndexPrefix, accrualHeightIndexPrefix, accrualTimeIndexPrefix,
+		statusTimeIndexPrefix, installmentTimeIndexPrefix, lineAccrualHeightIndexPrefix, lineAccrualTimeIndexPrefix} {
+		keeper.clearPrefix(ctx, []byte(prefix))
+	}
+	for _, debt := range keeper.GetAllDebts(ctx) {
+		keeper.setDebtIndexes(ctx, debt)
+	}
+	for _, line := range keeper.GetAllCreditLines(ctx) {
+		keeper.setCreditLineIndexes(ctx, line)
+	}
+
+	store.Set(debtIndexesVersionKey, keeper.cdc.MustMarshalBinaryBare(debtIndexesVersion))
+}
+
+func (keeper Keeper) clearPrefix(ctx sdk.Context, prefix []byte) {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, prefix)
+
+	// keys cannot be deleted while iterating
+	var keys [][]byte
+	for ; ri.Valid(); ri.Next() {
+		keys = append(keys, ri.Key())
+	}
+	ri.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
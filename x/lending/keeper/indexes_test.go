@@ -0,0 +1,178 @@
+package keeper
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_DebtIndexesFollowUpdates(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+	require.Len(t, keeper.GetDebtorDebts(ctx, debtor), 1)
+	require.Len(t, keeper.GetCreditorDebts(ctx, creditor), 1)
+	require.Empty(t, keeper.GetDebtorDebts(ctx, creditor))
+
+	// the indexes follow a change of creditor
+	debt.Creditor = other
+	require.NoError(t, keeper.updateDebt(ctx, debt))
+	require.Empty(t, keeper.GetCreditorDebts(ctx, creditor))
+	require.Equal(t, []types.Debt{debt}, keeper.GetCreditorDebts(ctx, other))
+	require.Equal(t, []types.Debt{debt}, keeper.GetDebtorDebts(ctx, debtor))
+}
+
+func TestKeeper_MigrateDebtIndexes(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	// a store written before the indexes existed only holds the debts
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getDebtStoreKey(debt.ID), keeper.cdc.MustMarshalBinaryBare(&debt))
+	require.Empty(t, keeper.GetDebtorDebts(ctx, debtor))
+
+	keeper.MigrateDebtIndexes(ctx)
+	require.Equal(t, []types.Debt{debt}, keeper.GetDebtorDebts(ctx, debtor))
+	require.Equal(t, []types.Debt{debt}, keeper.GetCreditorDebts(ctx, creditor))
+
+	// once migrated, the indexes are not rebuilt anymore
+	store.Set(getDebtStoreKey("A2"), keeper.cdc.MustMarshalBinaryBare(&debt))
+	keeper.MigrateDebtIndexes(ctx)
+	require.Len(t, keeper.GetDebtorDebts(ctx, debtor), 1)
+}
+
+func TestKeeper_MigrateDueIndexes(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	terms := types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+
+	// a store written before the due indexes existed, but with the first version of the others
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+	debt.Interest = terms
+	line := types.NewCreditLine("L1", creditor, debtor, sdk.NewCoin("foo", sdk.NewInt(100)))
+	line.Interest = terms
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getDebtStoreKey(debt.ID), keeper.cdc.MustMarshalBinaryBare(&debt))
+	store.Set(getCreditLineStoreKey(line.ID), keeper.cdc.MustMarshalBinaryBare(&line))
+	store.Set(debtIndexesVersionKey, keeper.cdc.MustMarshalBinaryBare(uint64(1)))
+	require.Empty(t, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 10))
+
+	keeper.MigrateDebtIndexes(ctx)
+	require.Equal(t, []string{"A1"}, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 10))
+	require.Equal(t, []string{"L1"}, keeper.getDueHeightIDs(ctx, lineAccrualHeightIndexPrefix, 10))
+}
+
+func TestKeeper_DueIndexesFollowUpdates(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	maturity := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	// a debt without interest nor maturity is never due
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A0", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+	debt.MaturityTime = maturity
+	debt.GracePeriod = time.Hour
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+	require.Empty(t, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 9))
+	require.Equal(t, []string{"A1"}, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 10))
+	require.Empty(t, keeper.getDueTimeIDs(ctx, statusTimeIndexPrefix, maturity))
+	require.Equal(t, []string{"A1"}, keeper.getDueTimeIDs(ctx, statusTimeIndexPrefix, maturity.Add(time.Second)))
+
+	// the debt is due again at the end of its next interest period
+	keeper.AccrueInterest(ctx.WithBlockHeight(10))
+	require.Empty(t, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 19))
+	require.Equal(t, []string{"A1"}, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 20))
+
+	// and its status is due again at the end of its grace period
+	keeper.UpdateDebtStatuses(ctx.WithBlockTime(maturity.Add(time.Second)))
+	require.Empty(t, keeper.getDueTimeIDs(ctx, statusTimeIndexPrefix, maturity.Add(time.Hour)))
+	require.Equal(t, []string{"A1"}, keeper.getDueTimeIDs(ctx, statusTimeIndexPrefix, maturity.Add(time.Hour+time.Second)))
+
+	// closed debts are never due
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebtInFull("A1", debtor)))
+	require.Empty(t, keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, 1000))
+	require.Empty(t, keeper.getDueTimeIDs(ctx, statusTimeIndexPrefix, maturity.Add(1000*time.Hour)))
+}
+
+func TestKeeper_BlockHooksSkipIdleDebts(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	// the gas that the block hooks read from the store
+	hooksGas := func(keeper Keeper, ctx sdk.Context) uint64 {
+		ctx = ctx.WithBlockHeight(5).WithGasMeter(sdk.NewInfiniteGasMeter())
+		keeper.AccrueInterest(ctx)
+		keeper.AccrueCreditLineInterest(ctx)
+		keeper.UpdateDebtStatuses(ctx)
+		keeper.UpdateInstallmentStatuses(ctx)
+		return ctx.GasMeter().GasConsumed()
+	}
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	withoutDebts := hooksGas(keeper, ctx)
+
+	// debts and lines that accrue nothing and have no deadline cost nothing to the hooks
+	for i := 0; i < 100; i++ {
+		id := fmt.Sprintf("A%d", i)
+		require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt(id, debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+		keeper.SetCreditLine(ctx, types.NewCreditLine(fmt.Sprintf("L%d", i), creditor, debtor, sdk.NewCoin("foo", sdk.NewInt(100))))
+	}
+	require.Equal(t, withoutDebts, hooksGas(keeper, ctx))
+}
+
+// benchmarkDebtorDebts measures the cost of looking up the debts of a debtor
+// among many debts of other debtors
+func benchmarkDebtorDebts(b *testing.B, lookup func(keeper Keeper, ctx sdk.Context, address sdk.AccAddress) []types.Debt) {
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	debtor := sdk.AccAddress([]byte("debtor______________"))
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	for i := 0; i < 5000; i++ {
+		other := sdk.AccAddress([]byte(fmt.Sprintf("other%015d", i)))
+		require.NoError(b, keeper.CreateDebt(ctx, types.NewDebt(fmt.Sprintf("B%d", i), other, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(b, keeper.CreateDebt(ctx, types.NewDebt(fmt.Sprintf("A%d", i), debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		require.Len(b, lookup(keeper, ctx, debtor), 10)
+	}
+}
+
+func BenchmarkGetDebtorDebts_Index(b *testing.B) {
+	benchmarkDebtorDebts(b, func(keeper Keeper, ctx sdk.Context, address sdk.AccAddress) []types.Debt {
+		return keeper.GetDebtorDebts(ctx, address)
+	})
+}
+
+func BenchmarkGetDebtorDebts_FullScan(b *testing.B) {
+	benchmarkDebtorDebts(b, func(keeper Keeper, ctx sdk.Context, address sdk.AccAddress) []types.Debt {
+		return keeper.getDebts(ctx, func(debt types.Debt) bool {
+			return debt.Debtor.Equals(address)
+		})
+	})
+}
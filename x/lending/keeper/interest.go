@@ -0,0 +1,99 @@
+package keeper
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// AccrueInterest updates the accrued interest of all debts whose
+// accrual period has elapsed since their last accrual. Only the debts
+// due in the accrual indexes are loaded
+func (keeper Keeper) AccrueInterest(ctx sdk.Context) {
+	ids := append(keeper.getDueHeightIDs(ctx, accrualHeightIndexPrefix, ctx.BlockHeight()),
+		keeper.getDueTimeIDs(ctx, accrualTimeIndexPrefix, ctx.BlockTime().Add(time.Nanosecond))...)
+	debts := keeper.getDueDebts(ctx, ids)
+
+	for _, debt := range debts {
+		if accrued, ok := accrueDebtInterest(debt, ctx.BlockHeight(), ctx.BlockTime()); ok {
+			if err := keeper.updateDebt(ctx, accrued); err != nil {
+				panic(err)
+			}
+
+			// periods that accrue less than a coin leave no entry in the ledger
+			interest := accrued.AccruedInterest.Sub(debt.AccruedInterest)
+			if interest.IsZero() {
+				continue
+			}
+
+			entryType := types.EntryInterest
+			if debt.InPenalty() {
+				entryType = types.EntryPenalty
+			}
+			keeper.recordEntry(ctx, entryType, accrued, interest)
+		}
+	}
+}
+
+// accrueDebtInterest yields the debt with the interest of all the periods elapsed
+// up to the given height and time, at the penalty rate if the debt is in penalty,
+// and true if some interest period has elapsed. The fractions of coin are carried
+// to the next accrual. If the interest overflows, the debt is left as it is
+// and false is returned, so that a runaway debt cannot halt the chain
+func accrueDebtInterest(debt types.Debt, height int64, blockTime time.Time) (accrued types.Debt, ok bool) {
+	terms := debt.CurrentInterest()
+	if terms.IsZero() {
+		return debt, false
+	}
+
+	periods := elapsedPeriods(terms, debt.LastAccrualHeight, debt.LastAccrualTime, height, blockTime)
+	if periods <= 0 {
+		return debt, false
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			accrued, ok = debt, false
+		}
+	}()
+
+	// the interest of each denom accrues on the principal and interest in that denom
+	interest := make([]sdk.Coin, len(debt.Amount))
+	remainder := make([]sdk.DecCoin, len(debt.Amount))
+	for i, coin := range debt.Amount {
+		carried := debt.InterestRemainder.AmountOf(coin.Denom)
+		exact := terms.ExactInterestFor(coin.Amount, debt.AccruedInterest.AmountOf(coin.Denom).ToDec().Add(carried), periods).Add(carried)
+
+		whole := exact.TruncateInt()
+		interest[i] = sdk.NewCoin(coin.Denom, whole)
+		remainder[i] = sdk.NewDecCoinFromDec(coin.Denom, exact.Sub(whole.ToDec()))
+	}
+
+	accrued = debt
+	accrued.AccruedInterest = debt.AccruedInterest.Add(types.NewDebtCoins(interest...))
+	accrued.InterestRemainder = sdk.NewDecCoins(remainder...)
+	accrued.LastAccrualHeight, accrued.LastAccrualTime = advanceAccrual(terms, debt.LastAccrualHeight, debt.LastAccrualTime, periods)
+	return accrued, true
+}
+
+// elapsedPeriods yields the number of whole interest periods of the given terms
+// elapsed between the last accrual and the given height and time
+func elapsedPeriods(terms types.InterestTerms, lastHeight int64, lastTime time.Time, height int64, blockTime time.Time) int64 {
+	if terms.PeriodUnit == types.PeriodSeconds {
+		return int64(blockTime.Sub(lastTime)/time.Second) / terms.Period
+	}
+
+	return (height - lastHeight) / terms.Period
+}
+
+// advanceAccrual yields the height and time of the last accrual moved forward by the given
+// periods. We only move forward by whole periods, so that the elapsed part of the current
+// period is not lost
+func advanceAccrual(terms types.InterestTerms, lastHeight int64, lastTime time.Time, periods int64) (int64, time.Time) {
+	if terms.PeriodUnit == types.PeriodSeconds {
+		return lastHeight, lastTime.Add(time.Duration(periods*terms.Period) * time.Second)
+	}
+
+	return lastHeight + periods*terms.Period, lastTime
+}
@@ -0,0 +1,196 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_AccrueInterest(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(10000))
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		interest         types.InterestTerms
+		height           int64
+		blockTime        time.Time
+		expectedInterest sdk.Int
+	}{
+		{
+			"interest-free debt",
+			types.NoInterest(),
+			100,
+			start.Add(time.Hour),
+			sdk.ZeroInt(),
+		},
+		{
+			"period not elapsed yet",
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks),
+			9,
+			start,
+			sdk.ZeroInt(),
+		},
+		{
+			"simple interest over blocks",
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks),
+			35,
+			start,
+			sdk.NewInt(300),
+		},
+		{
+			"compound interest over blocks",
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 1), types.InterestCompound, 10, types.PeriodBlocks),
+			20,
+			start,
+			sdk.NewInt(2100),
+		},
+		{
+			"simple interest over seconds",
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 60, types.PeriodSeconds),
+			1,
+			start.Add(150 * time.Second),
+			sdk.NewInt(200),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+
+			debt := types.NewDebt("A1", debtor, amount, creditor)
+			debt.Interest = tt.interest
+			require.NoError(t, keeper.activateDebt(ctx.WithBlockTime(start), debt))
+
+			keeper.AccrueInterest(ctx.WithBlockHeight(tt.height).WithBlockTime(tt.blockTime))
+
+			newDebt, err := keeper.GetDebt(ctx, debt.ID)
+			require.NoError(t, err)
+			require.True(t, newDebt.Amount.Coins().IsEqual(sdk.NewCoins(amount)))
+			require.True(t, newDebt.AccruedInterest.AmountOf("foo").Equal(tt.expectedInterest))
+		})
+	}
+}
+
+func TestKeeper_AccrueInterestKeepsPartialPeriods(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(10000)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+	require.NoError(t, keeper.activateDebt(ctx, debt))
+
+	// accruing at every block yields the same result as accruing once
+	for height := int64(1); height <= 25; height++ {
+		keeper.AccrueInterest(ctx.WithBlockHeight(height))
+	}
+
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.AmountOf("foo").Equal(sdk.NewInt(200)))
+	require.Equal(t, int64(20), newDebt.LastAccrualHeight)
+}
+
+func TestKeeper_AccrueInterestCarriesFractions(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(10)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(3, 2), types.InterestSimple, 1, types.PeriodBlocks)
+	require.NoError(t, keeper.activateDebt(ctx, debt))
+
+	// each block accrues 0.3 coins, that add up instead of being truncated away
+	for height := int64(1); height <= 10; height++ {
+		keeper.AccrueInterest(ctx.WithBlockHeight(height))
+	}
+
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.AmountOf("foo").Equal(sdk.NewInt(3)))
+	require.True(t, newDebt.InterestRemainder.IsZero())
+
+	keeper.AccrueInterest(ctx.WithBlockHeight(11))
+
+	newDebt, err = keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.AmountOf("foo").Equal(sdk.NewInt(3)))
+	require.Equal(t, sdk.NewDecCoins(sdk.NewDecCoinFromDec("foo", sdk.NewDecWithPrec(3, 1))), newDebt.InterestRemainder)
+}
+
+func TestKeeper_ActivateDebtResetsRemainder(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	// a remainder set by the creditor would be owed by the debtor at the next accrual
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 8), types.InterestSimple, 1, types.PeriodBlocks)
+	debt.InterestRemainder = sdk.NewDecCoins(sdk.NewDecCoin("foo", sdk.NewInt(5000)))
+	require.Error(t, types.NewMsgProposeDebt(debt, 0).ValidateBasic())
+	require.Contains(t, debt.String(), "Interest remainder: 5000")
+	require.NoError(t, keeper.DisburseDebt(ctx, debt))
+
+	keeper.AccrueInterest(ctx.WithBlockHeight(1))
+
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.IsZero())
+}
+
+func TestKeeper_AccrueInterestSkipsOverflow(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	// a debt that doubles at each block, as imported from a genesis beyond the caps
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(10000)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.OneDec(), types.InterestCompound, 1, types.PeriodBlocks)
+	require.NoError(t, keeper.activateDebt(ctx, debt))
+
+	require.NotPanics(t, func() {
+		keeper.AccrueInterest(ctx.WithBlockHeight(1000))
+	})
+
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.IsZero())
+	require.Equal(t, int64(0), newDebt.LastAccrualHeight)
+}
+
+func TestKeeper_PayDebtCoversInterestFirst(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(10000)), creditor)
+	debt.AccruedInterest = types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(500)))
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	// the payment only covers part of the interest
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt(debt.ID, sdk.NewCoin("foo", sdk.NewInt(200)), debtor)))
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.AmountOf("foo").Equal(sdk.NewInt(300)))
+	require.True(t, newDebt.Amount.AmountOf("foo").Equal(sdk.NewInt(10000)))
+
+	// the payment covers the rest of the interest and part of the principal
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt(debt.ID, sdk.NewCoin("foo", sdk.NewInt(800)), debtor)))
+	newDebt, err = keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedInterest.IsZero())
+	require.True(t, newDebt.Amount.AmountOf("foo").Equal(sdk.NewInt(9500)))
+}
@@ -1,6 +1,8 @@
 package keeper
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spoto/lending/x/lending/types"
 )
@@ -8,6 +10,9 @@ import (
 func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
 	ir.RegisterRoute(types.ModuleName, "debts_are_positive", DebtsArePositive(k))
 	ir.RegisterRoute(types.ModuleName, "debts_are_not_reflexive", DebtsAreNotReflexive(k))
+	ir.RegisterRoute(types.ModuleName, "locked_coins_are_held", LockedCoinsAreHeld(k))
+	ir.RegisterRoute(types.ModuleName, "credit_lines_within_limits", CreditLinesWithinLimits(k))
+	ir.RegisterRoute(types.ModuleName, "pool_shares_are_minted", PoolSharesAreMinted(k))
 }
 
 func DebtsArePositive(keeper Keeper) sdk.Invariant {
@@ -15,12 +20,12 @@ func DebtsArePositive(keeper Keeper) sdk.Invariant {
 		debts := keeper.GetAllDebts(ctx)
 		negative := false
 		for _, debt := range debts {
-			negative = negative || debt.Amount.IsNegative()
+			negative = negative || debt.Amount.IsAnyNegative() || debt.AccruedInterest.IsAnyNegative() || debt.AccruedFees.IsAnyNegative()
 		}
 
 		return sdk.FormatInvariant(types.ModuleName,
 			"negative debt",
-			"A debt has a negative amount"),
+			"A debt has a negative amount, accrued interest or accrued fees"),
 			negative
 	}
 }
@@ -38,4 +43,52 @@ func DebtsAreNotReflexive(keeper Keeper) sdk.Invariant {
 			"The creditor of a debt coincides with its debtor"),
 			reflexive
 	}
-}
\ No newline at end of file
+}
+
+// LockedCoinsAreHeld checks that the module account holds exactly the locked collateral
+// of the debts, the escrowed principal of the proposals and the escrow of the credit lines
+func LockedCoinsAreHeld(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		locked := keeper.GetLockedCoins(ctx)
+		held := keeper.GetModuleAccountCoins(ctx)
+		// IsEqual panics on coins of different denoms, that is, on a broken invariant
+		broken := !held.IsAllGTE(locked) || !locked.IsAllGTE(held)
+
+		return sdk.FormatInvariant(types.ModuleName,
+			"locked coins",
+			fmt.Sprintf("The module account holds %s but the locked coins are %s", held, locked)),
+			broken
+	}
+}
+
+// CreditLinesWithinLimits checks that nothing is drawn from a credit line beyond its limit
+func CreditLinesWithinLimits(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		broken := false
+		for _, line := range keeper.GetAllCreditLines(ctx) {
+			broken = broken || line.Drawn.IsNegative() || line.Drawn.GT(line.Limit) || line.AccruedInterest.IsNegative()
+		}
+
+		return sdk.FormatInvariant(types.ModuleName,
+			"credit lines",
+			"A credit line is drawn beyond its limit, or has a negative drawn amount or accrued interest"),
+			broken
+	}
+}
+
+// PoolSharesAreMinted checks that the supply of the share tokens of each pool is exactly its total shares
+func PoolSharesAreMinted(keeper Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		supply := keeper.supplyKeeper.GetSupply(ctx).GetTotal()
+
+		broken := false
+		for _, pool := range keeper.GetAllPools(ctx) {
+			broken = broken || !supply.AmountOf(pool.ShareDenom()).Equal(pool.TotalShares)
+		}
+
+		return sdk.FormatInvariant(types.ModuleName,
+			"pool shares",
+			"The supply of the share tokens of a pool differs from its total shares"),
+			broken
+	}
+}
@@ -1,9 +1,9 @@
 package keeper
 
 import (
-	"fmt"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/spoto/lending/x/lending/types"
 )
@@ -14,17 +14,25 @@ type Keeper struct {
 	// we include the keeper of Cosmos' Bank module
 	bankKeeper bank.Keeper
 
+	// we use the supply keeper to escrow coins in the lending module account
+	supplyKeeper types.SupplyKeeper
+
 	// we add an extra keeper to keep all debts created so far
-	storeKey   sdk.StoreKey
+	storeKey sdk.StoreKey
+
+	// the parameters of the module, such as the whitelisted price oracles
+	paramspace types.ParamSubspace
 
-	cdc        *codec.Codec
+	cdc *codec.Codec
 }
 
-func NewKeeper(storeKey sdk.StoreKey, bankKeeper bank.Keeper, cdc *codec.Codec) Keeper {
+func NewKeeper(storeKey sdk.StoreKey, bankKeeper bank.Keeper, supplyKeeper types.SupplyKeeper, paramspace types.ParamSubspace, cdc *codec.Codec) Keeper {
 	return Keeper{
-		storeKey:   storeKey,
-		bankKeeper: bankKeeper,
-		cdc:        cdc,
+		storeKey:     storeKey,
+		bankKeeper:   bankKeeper,
+		supplyKeeper: supplyKeeper,
+		paramspace:   paramspace.WithKeyTable(types.ParamKeyTable()),
+		cdc:          cdc,
 	}
 }
 
@@ -34,12 +42,46 @@ func getDebtStoreKey(ID string) []byte {
 	return []byte(debtStorePrefix + ID)
 }
 
-func (keeper Keeper) getDebtByID(ctx sdk.Context, id string) (types.Debt, error) {
+var nextDebtSequenceKey = []byte(":nextdebtsequence:")
+
+// GetNextDebtSequence yields the sequence number of the next ID that the keeper assigns
+func (keeper Keeper) GetNextDebtSequence(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(keeper.storeKey)
+	if !store.Has(nextDebtSequenceKey) {
+		return types.DefaultNextDebtSequence
+	}
+
+	var sequence uint64
+	keeper.cdc.MustUnmarshalBinaryBare(store.Get(nextDebtSequenceKey), &sequence)
+	return sequence
+}
+
+func (keeper Keeper) SetNextDebtSequence(ctx sdk.Context, sequence uint64) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(nextDebtSequenceKey, keeper.cdc.MustMarshalBinaryBare(sequence))
+}
+
+// NextDebtID assigns a fresh ID from the sequence counter
+func (keeper Keeper) NextDebtID(ctx sdk.Context) string {
+	for {
+		sequence := keeper.GetNextDebtSequence(ctx)
+		keeper.SetNextDebtSequence(ctx, sequence+1)
+
+		// IDs imported at genesis might already use the sequence number
+		id := types.FormatAutoID(sequence)
+		if !keeper.isUsedID(ctx, id) {
+			return id
+		}
+	}
+}
+
+// GetDebt yields the debt with the given ID
+func (keeper Keeper) GetDebt(ctx sdk.Context, id string) (types.Debt, error) {
 	store := ctx.KVStore(keeper.storeKey)
 
 	debtKey := getDebtStoreKey(id)
 	if !store.Has(debtKey) {
-		return types.Debt{}, fmt.Errorf("cannot find debt with ID %s", id)
+		return types.Debt{}, sdkErr.Wrapf(types.ErrDebtNotFound, "cannot find debt with ID %s", id)
 	}
 
 	var debt types.Debt
@@ -50,12 +92,83 @@ func (keeper Keeper) getDebtByID(ctx sdk.Context, id string) (types.Debt, error)
 func (keeper Keeper) CreateDebt(ctx sdk.Context, debt types.Debt) error {
 	store := ctx.KVStore(keeper.storeKey)
 
-	if !store.Has(getDebtStoreKey(debt.ID)) {
+	// debts that do not specify interest are interest-free
+	if debt.Interest.Rate.IsNil() {
+		debt.Interest = types.NoInterest()
+	}
+	if len(debt.AccruedInterest) == 0 {
+		debt.AccruedInterest = debt.Amount.Zero()
+	}
+	if debt.LateFees.Rate.IsNil() {
+		debt.LateFees.Rate = sdk.ZeroDec()
+	}
+	if debt.LateFees.PenaltyRate.IsNil() {
+		debt.LateFees.PenaltyRate = sdk.ZeroDec()
+	}
+	if len(debt.AccruedFees) == 0 {
+		debt.AccruedFees = debt.Amount.Zero()
+	}
+	if debt.Status == "" {
+		debt.Status = types.StatusActive
+	}
+	if len(debt.Settlement.PrincipalPaid) == 0 {
+		debt.Settlement = types.NewSettlement(debt.Amount)
+	}
+
+	if !keeper.isUsedID(ctx, debt.ID) {
 		store.Set(getDebtStoreKey(debt.ID), keeper.cdc.MustMarshalBinaryBare(&debt))
+		keeper.setDebtIndexes(ctx, debt)
 		return nil
 	}
 
-	return fmt.Errorf("cannot create a debt with an already used ID %s", debt.ID)
+	return sdkErr.Wrapf(types.ErrDuplicateID, "cannot create a debt with an already used ID %s", debt.ID)
+}
+
+// DisburseDebt transfers the principal of the debt from its creditor
+// to its debtor, locks its collateral and activates the debt
+func (keeper Keeper) DisburseDebt(ctx sdk.Context, debt types.Debt) error {
+	if keeper.isUsedID(ctx, debt.ID) {
+		return sdkErr.Wrapf(types.ErrDuplicateID, "cannot create a debt with an already used ID %s", debt.ID)
+	}
+
+	if err := keeper.applyParams(ctx, &debt); err != nil {
+		return err
+	}
+
+	if err := keeper.lockCollateral(ctx, debt); err != nil {
+		return err
+	}
+
+	if err := keeper.bankKeeper.SendCoins(ctx, debt.Creditor, debt.Debtor, debt.Amount.Coins()); err != nil {
+		return err
+	}
+
+	if err := keeper.activateDebt(ctx, debt); err != nil {
+		return err
+	}
+
+	keeper.emitDebtEvent(ctx, types.ActionCreate, debt, debt.Amount.String())
+	return nil
+}
+
+// GetModuleAccountCoins yields the coins held in custody by the lending module account
+func (keeper Keeper) GetModuleAccountCoins(ctx sdk.Context) sdk.Coins {
+	return keeper.bankKeeper.GetCoins(ctx, keeper.supplyKeeper.GetModuleAddress(types.ModuleName))
+}
+
+// activateDebt stores a debt whose principal has just been disbursed.
+// Interest starts accruing and installments start falling due from the current block
+func (keeper Keeper) activateDebt(ctx sdk.Context, debt types.Debt) error {
+	debt.Principal = debt.Amount
+	debt.AccruedInterest = debt.Amount.Zero()
+	debt.InterestRemainder = nil
+	debt.AccruedFees = debt.Amount.Zero()
+	debt.Settlement = types.NewSettlement(debt.Amount)
+	debt.Schedule = debt.Repayment.Generate(debt.Amount, debt.Interest, ctx.BlockTime())
+	debt.LastAccrualHeight = ctx.BlockHeight()
+	debt.LastAccrualTime = ctx.BlockTime()
+	debt.Status = types.StatusActive
+	return keeper.CreateDebt(ctx, debt)
 }
 
 func (keeper Keeper) debtsIterator(ctx sdk.Context) sdk.Iterator {
@@ -92,71 +205,150 @@ func (keeper Keeper) GetAllDebts(ctx sdk.Context) []types.Debt {
 }
 
 func (keeper Keeper) GetDebtorDebts(ctx sdk.Context, address sdk.AccAddress) []types.Debt {
-	// we use a filter that projects on the debtor
-	return keeper.getDebts(ctx, func(debt types.Debt) bool {
-		return debt.Debtor.Equals(address)
-	})
+	// we only scan the index of the debtor
+	return keeper.getIndexedDebts(ctx, getDebtorIndexPrefix(address))
 }
 
 func (keeper Keeper) GetCreditorDebts(ctx sdk.Context, address sdk.AccAddress) []types.Debt {
-	// we use a filter that projects on the creditor
-	return keeper.getDebts(ctx, func(debt types.Debt) bool {
-		return debt.Creditor.Equals(address)
-	})
+	// we only scan the index of the creditor
+	return keeper.getIndexedDebts(ctx, getCreditorIndexPrefix(address))
 }
 
 func (keeper Keeper) PayDebt(ctx sdk.Context, msg types.MsgPayDebt) error {
-	debt, err := keeper.getDebtByID(ctx, msg.ID)
+	debt, err := keeper.GetDebt(ctx, msg.ID)
 	if err != nil {
 		return err
 	}
 
 	if !msg.Debtor.Equals(debt.Debtor) {
-		return fmt.Errorf("the debt with ID %s is not yours", msg.ID)
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the debt with ID %s is not yours", msg.ID)
+	}
+
+	if debt.Status.IsClosed() {
+		return sdkErr.Wrapf(types.ErrDebtClosed, "the debt with ID %s is already %s", msg.ID, debt.Status)
 	}
 
-	if err := keeper.bankKeeper.SendCoins(ctx, debt.Debtor, debt.Creditor, sdk.NewCoins(msg.Amount)); err != nil {
+	payment := msg.Amount
+	if msg.InFull {
+		payment = debt.Owed()
+	}
+
+	if err := checkDenoms(debt, payment); err != nil {
+		return err
+	}
+
+	// the debtor never pays more than what is owed, in each denom
+	payment = payment.Min(debt.Owed())
+
+	if err := keeper.bankKeeper.SendCoins(ctx, debt.Debtor, debt.Creditor, payment.Coins()); err != nil {
 		return err
 	}
 
-	if msg.Amount.IsLT(debt.Amount) {
-		debt.Amount = debt.Amount.Sub(msg.Amount)
+	// payments cover the late fees first, then the accrued interest and then the principal
+	fees := payment.Min(debt.AccruedFees)
+	scheduled := payment.Sub(fees)
+	interest := scheduled.Min(debt.AccruedInterest)
+	principal := scheduled.Sub(interest)
+
+	debt.AccruedFees = debt.AccruedFees.Sub(fees)
+	debt.AccruedInterest = debt.AccruedInterest.Sub(interest)
+	debt.Amount = debt.Amount.Sub(principal)
+	debt.Settlement = debt.Settlement.AddPaid(principal, interest).AddFeesPaid(fees)
+	debt.Schedule = debt.Schedule.Allocate(scheduled)
+
+	if debt.Owed().IsZero() {
+		debt.Status = types.StatusRepaid
+		if err := keeper.releaseCollateral(ctx, &debt); err != nil {
+			return err
+		}
+		err = keeper.closeDebt(ctx, debt)
 	} else {
-		debt.Amount = sdk.NewCoin(debt.Amount.Denom, sdk.NewInt(0))
+		err = keeper.updateDebt(ctx, debt)
+	}
+	if err != nil {
+		return err
 	}
 
-	return keeper.updateDebt(ctx, debt)
+	keeper.recordEntry(ctx, types.EntryPayment, debt, payment)
+	keeper.emitDebtEvent(ctx, types.ActionPay, debt, payment.String())
+	return nil
 }
 
 func (keeper Keeper) ChangeDebt(ctx sdk.Context, msg types.MsgChangeDebt) error {
-	debt, err := keeper.getDebtByID(ctx, msg.ID)
+	debt, err := keeper.GetDebt(ctx, msg.ID)
 	if err != nil {
 		return err
 	}
 
 	if !msg.Creditor.Equals(debt.Creditor) {
-		return fmt.Errorf("the debt with ID %s is not yours", msg.ID)
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the debt with ID %s is not yours", msg.ID)
 	}
 
-	if msg.Amount.IsGTE(debt.Amount) {
-		return fmt.Errorf("the new amount can only be smaller than the original %s", debt.Amount)
+	if debt.Status.IsClosed() {
+		return sdkErr.Wrapf(types.ErrDebtClosed, "the debt with ID %s is already %s", msg.ID, debt.Status)
 	}
 
-	debt.Amount = debt.Amount.Sub(msg.Amount)
-	if debt.Amount.IsNegative() {
-		debt.Amount = sdk.NewCoin(debt.Amount.Denom, sdk.NewInt(0))
+	if err := checkDenoms(debt, msg.Amount); err != nil {
+		return err
 	}
 
-	return keeper.updateDebt(ctx, debt)
+	// the creditor can forgive at most what is owed, in each denom
+	if !debt.Owed().IsAllGTE(msg.Amount) {
+		return sdkErr.Wrapf(types.ErrInvalidAmount, "the forgiven amount cannot exceed what is owed %s", debt.Owed())
+	}
+
+	// forgiveness covers the late fees first, then the accrued interest and then the principal, as payments
+	fees := msg.Amount.Min(debt.AccruedFees)
+	scheduled := msg.Amount.Sub(fees)
+	interest := scheduled.Min(debt.AccruedInterest)
+	principal := scheduled.Sub(interest)
+
+	debt.AccruedFees = debt.AccruedFees.Sub(fees)
+	debt.AccruedInterest = debt.AccruedInterest.Sub(interest)
+	debt.Amount = debt.Amount.Sub(principal)
+	debt.Settlement = debt.Settlement.AddForgiven(msg.Amount)
+	debt.Schedule = debt.Schedule.Forgive(scheduled)
+
+	if debt.Owed().IsZero() {
+		debt.Status = types.StatusForgiven
+		if err := keeper.releaseCollateral(ctx, &debt); err != nil {
+			return err
+		}
+		err = keeper.closeDebt(ctx, debt)
+	} else {
+		err = keeper.updateDebt(ctx, debt)
+	}
+	if err != nil {
+		return err
+	}
+
+	keeper.recordEntry(ctx, types.EntryForgiveness, debt, msg.Amount)
+	keeper.emitDebtEvent(ctx, types.ActionChange, debt, msg.Amount.String())
+	return nil
+}
+
+// checkDenoms yields an error if the amount is in a denom that the debt is not in
+func checkDenoms(debt types.Debt, amount types.DebtCoins) error {
+	for _, coin := range amount {
+		if !debt.Amount.HasDenom(coin.Denom) {
+			return sdkErr.Wrapf(types.ErrDenomMismatch, "the debt with ID %s is in %s, not %s", debt.ID, debt.Amount, coin.Denom)
+		}
+	}
+
+	return nil
 }
 
 func (keeper Keeper) updateDebt(ctx sdk.Context, debt types.Debt) error {
 	store := ctx.KVStore(keeper.storeKey)
 
-	if !store.Has(getDebtStoreKey(debt.ID)) {
-		return fmt.Errorf("the debt with ID %s does not exist", debt.ID)
+	old, err := keeper.GetDebt(ctx, debt.ID)
+	if err != nil {
+		return sdkErr.Wrapf(types.ErrDebtNotFound, "the debt with ID %s does not exist", debt.ID)
 	}
 
+	// the parties of the debt might have changed
+	keeper.deleteDebtIndexes(ctx, old)
 	store.Set(getDebtStoreKey(debt.ID), keeper.cdc.MustMarshalBinaryBare(&debt))
+	keeper.setDebtIndexes(ctx, debt)
 	return nil
 }
@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spoto/lending/x/lending/types"
 	"github.com/stretchr/testify/require"
@@ -14,7 +15,7 @@ func TestKeeper_CreateDebtWithAvailableID(t *testing.T) {
 	debt1 := types.Debt{
 		ID:       "A1",
 		Debtor:   debtor,
-		Amount:   sdk.NewCoin("foo", sdk.NewInt(20000)),
+		Amount:   types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(20000))),
 		Creditor: creditor,
 	}
 
@@ -26,7 +27,7 @@ func TestKeeper_CreateDebtWithAvailableID(t *testing.T) {
 	debt2 := types.Debt{
 		ID:       "A2",
 		Debtor:   debtor,
-		Amount:   sdk.NewCoin("foo", sdk.NewInt(20000)),
+		Amount:   types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(20000))),
 		Creditor: creditor,
 	}
 
@@ -42,7 +43,7 @@ func TestKeeper_CreateDebtWithUnavailableID(t *testing.T) {
 	debt1 := types.Debt{
 		ID:       "A1",
 		Debtor:   debtor,
-		Amount:   sdk.NewCoin("foo", sdk.NewInt(20000)),
+		Amount:   types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(20000))),
 		Creditor: creditor,
 	}
 
@@ -53,7 +54,7 @@ func TestKeeper_CreateDebtWithUnavailableID(t *testing.T) {
 	debt2 := types.Debt{
 		ID:       "A1",  // same ID as before!
 		Debtor:   creditor,
-		Amount:   sdk.NewCoin("foo", sdk.NewInt(10000)),
+		Amount:   types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(10000))),
 		Creditor: debtor,
 	}
 
@@ -61,6 +62,37 @@ func TestKeeper_CreateDebtWithUnavailableID(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestKeeper_DisburseDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(20000))
+	debt := types.Debt{
+		ID:       "A1",
+		Debtor:   debtor,
+		Amount:   types.NewDebtCoins(amount),
+		Creditor: creditor,
+	}
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+
+	// the creditor has not enough funds to finance the debt
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10000)))))
+	require.Error(t, keeper.DisburseDebt(ctx, debt))
+	require.Empty(t, keeper.GetAllDebts(ctx))
+
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount)))
+	require.NoError(t, keeper.DisburseDebt(ctx, debt))
+
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().Empty())
+	require.True(t, authKeeper.GetAccount(ctx, debtor).GetCoins().IsEqual(sdk.NewCoins(amount)))
+
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.True(t, newDebt.Principal.Coins().IsEqual(sdk.NewCoins(amount)))
+	require.True(t, newDebt.Amount.Coins().IsEqual(sdk.NewCoins(amount)))
+}
+
 func TestKeeper_PayDebt(t *testing.T) {
 	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
 	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
@@ -80,7 +112,7 @@ func TestKeeper_PayDebt(t *testing.T) {
 			nil,
 			types.MsgPayDebt{
 				ID:     ID,
-				Amount: amount,
+				Amount: types.NewDebtCoins(amount),
 				Debtor: debtor,
 			},
 			nil,
@@ -91,12 +123,12 @@ func TestKeeper_PayDebt(t *testing.T) {
 			&types.Debt{
 				ID:       ID,
 				Debtor:   debtor,
-				Amount:   amount,
+				Amount:   types.NewDebtCoins(amount),
 				Creditor: creditor,
 			},
 			types.MsgPayDebt{
 				ID:     ID,
-				Amount: amount,
+				Amount: types.NewDebtCoins(amount),
 				Debtor: debtor,
 			},
 			nil,
@@ -107,12 +139,12 @@ func TestKeeper_PayDebt(t *testing.T) {
 			&types.Debt{
 				ID:       ID,
 				Debtor:   debtor,
-				Amount:   amount,
+				Amount:   types.NewDebtCoins(amount),
 				Creditor: creditor,
 			},
 			types.MsgPayDebt{
 				ID:     ID,
-				Amount: amount,
+				Amount: types.NewDebtCoins(amount),
 				Debtor: creditor,
 			},
 			nil,
@@ -123,12 +155,12 @@ func TestKeeper_PayDebt(t *testing.T) {
 			&types.Debt{
 				ID:       ID,
 				Debtor:   debtor,
-				Amount:   amount,
+				Amount:   types.NewDebtCoins(amount),
 				Creditor: creditor,
 			},
 			types.MsgPayDebt{
 				ID:     ID,
-				Amount: amount,
+				Amount: types.NewDebtCoins(amount),
 				Debtor: debtor,
 			},
 			sdk.NewCoins(amount),
@@ -140,28 +172,56 @@ func TestKeeper_PayDebt(t *testing.T) {
 			&types.Debt{
 				ID:       ID,
 				Debtor:   debtor,
-				Amount:   amount,
+				Amount:   types.NewDebtCoins(amount),
 				Creditor: creditor,
 			},
 			types.MsgPayDebt{
 				ID:     ID,
-				Amount: sdk.NewCoin("foo", sdk.NewInt(40000)),
+				Amount: types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(40000))),
 				Debtor: debtor,
 			},
 			sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(50000))),
 			false,
 		},
+		{
+			"totally pay debt in full mode",
+			&types.Debt{
+				ID:       ID,
+				Debtor:   debtor,
+				Amount:   types.NewDebtCoins(amount),
+				Creditor: creditor,
+			},
+			types.NewMsgPayDebtInFull(ID, debtor),
+			sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(50000))),
+			false,
+		},
+		{
+			"pay debt in another denom",
+			&types.Debt{
+				ID:       ID,
+				Debtor:   debtor,
+				Amount:   types.NewDebtCoins(amount),
+				Creditor: creditor,
+			},
+			types.MsgPayDebt{
+				ID:     ID,
+				Amount: types.NewDebtCoins(sdk.NewCoin("bar", sdk.NewInt(20000))),
+				Debtor: debtor,
+			},
+			sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(20000))),
+			true,
+		},
 		{
 			"partially pay debt",
 			&types.Debt{
 				ID:       ID,
 				Debtor:   debtor,
-				Amount:   amount,
+				Amount:   types.NewDebtCoins(amount),
 				Creditor: creditor,
 			},
 			types.MsgPayDebt{
 				ID:     ID,
-				Amount: sdk.NewCoin("foo", sdk.NewInt(10000)),
+				Amount: types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(10000))),
 				Debtor: debtor,
 			},
 			sdk.NewCoins(amount),
@@ -203,36 +263,215 @@ func TestKeeper_PayDebt(t *testing.T) {
 				return
 			}
 
+			// only what is owed gets paid, the excess stays with the debtor
+			paid := tt.preExistingDebt.Amount
+			if !tt.msgPayDebt.InFull {
+				paid = tt.msgPayDebt.Amount.Min(paid)
+			}
+
 			creditorAccount := authKeeper.GetAccount(ctx, tt.preExistingDebt.Creditor)
-			require.True(t, creditorAccount.GetCoins().IsEqual(sdk.NewCoins(tt.msgPayDebt.Amount)))
+			require.True(t, creditorAccount.GetCoins().IsEqual(paid.Coins()))
 
 			debtorAccount := authKeeper.GetAccount(ctx, tt.preExistingDebt.Debtor)
-			require.True(t, debtorAccount.GetCoins().IsEqual(tt.startingDebtorBalance.Sub(sdk.NewCoins(tt.msgPayDebt.Amount))))
+			require.True(t, debtorAccount.GetCoins().IsEqual(tt.startingDebtorBalance.Sub(paid.Coins())))
 
-			newDebt, err := keeper.getDebtByID(ctx, tt.msgPayDebt.ID)
-			require.NoError(t, err)
+			expectedAmount := tt.preExistingDebt.Amount.Sub(paid)
+
+			// repaid debts are moved to the archive
+			if expectedAmount.IsZero() {
+				_, err := keeper.GetDebt(ctx, tt.msgPayDebt.ID)
+				require.Error(t, err)
 
-			expectedAmount := sdk.NewCoin(tt.preExistingDebt.Amount.Denom, sdk.NewInt(0))
-			if tt.msgPayDebt.Amount.IsLT(tt.preExistingDebt.Amount) {
-				expectedAmount = tt.preExistingDebt.Amount.Sub(tt.msgPayDebt.Amount)
+				archived, err := keeper.GetArchivedDebt(ctx, tt.msgPayDebt.ID)
+				require.NoError(t, err)
+				require.Equal(t, types.StatusRepaid, archived.Debt.Status)
+				require.True(t, archived.Debt.Settlement.PrincipalPaid.Coins().IsEqual(paid.Coins()))
+				return
 			}
 
-			require.True(t, newDebt.Amount.IsEqual(expectedAmount))
+			newDebt, err := keeper.GetDebt(ctx, tt.msgPayDebt.ID)
+			require.NoError(t, err)
+			require.True(t, newDebt.Amount.Coins().IsEqual(expectedAmount.Coins()))
 		})
 	}
 }
 
-func TestKeeper_getDebtByID(t *testing.T) {
+func TestKeeper_PayDebtInFullWithInterest(t *testing.T) {
 	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
 	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
 
-	debt := types.Debt{
-		ID:       "A1",
-		Debtor:   debtor,
-		Amount:   sdk.NewCoin("foo", sdk.NewInt(20000)),
-		Creditor: creditor,
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.AccruedInterest = types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(50)))
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(2000)))))
+
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebtInFull("A1", debtor)))
+
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1050)))))
+	require.True(t, authKeeper.GetAccount(ctx, debtor).GetCoins().IsEqual(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(950)))))
+
+	archived, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.True(t, archived.Debt.Owed().IsZero())
+	require.Equal(t, types.StatusRepaid, archived.Debt.Status)
+	require.Equal(t, types.Settlement{
+		PrincipalPaid: types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(1000))),
+		InterestPaid:  types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(50))),
+		Forgiven:      types.NewDebtCoins(sdk.NewCoin("foo", sdk.ZeroInt())),
+		FeesPaid:      types.NewDebtCoins(sdk.NewCoin("foo", sdk.ZeroInt())),
+	}, archived.Debt.Settlement)
+}
+
+func TestKeeper_DisburseDebtResetsSettlement(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	// payments that never happened
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Settlement = debt.Settlement.AddPaid(types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(77))), nil)
+	require.Error(t, types.NewMsgCreateDebt(debt).ValidateBasic())
+	require.NoError(t, keeper.DisburseDebt(ctx, debt))
+
+	newDebt, err := keeper.GetDebt(ctx, debt.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.NewSettlement(debt.Amount), newDebt.Settlement)
+}
+
+func TestKeeper_PayMultiCoinDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	debt := types.NewMultiCoinDebt("A1", debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)), sdk.NewCoin("bar", sdk.NewInt(500))), creditor)
+	debt.AccruedInterest = types.NewDebtCoins(sdk.NewCoin("bar", sdk.ZeroInt()), sdk.NewCoin("foo", sdk.NewInt(50)))
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(
+		sdk.NewCoin("foo", sdk.NewInt(2000)), sdk.NewCoin("bar", sdk.NewInt(2000)), sdk.NewCoin("baz", sdk.NewInt(2000)))))
+
+	// a payment can only be in the denoms of the debt
+	err := keeper.PayDebt(ctx, types.NewMsgPayDebtCoins("A1", sdk.NewCoins(sdk.NewCoin("baz", sdk.NewInt(100))), debtor))
+	require.True(t, types.ErrDenomMismatch.Is(err))
+
+	// the bar balance is settled, while the foo payment covers the interest first
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebtCoins("A1",
+		sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(300)), sdk.NewCoin("bar", sdk.NewInt(800))), debtor)))
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.StatusActive, newDebt.Status)
+	require.True(t, newDebt.Amount.AmountOf("foo").Equal(sdk.NewInt(750)))
+	require.True(t, newDebt.Amount.AmountOf("bar").IsZero())
+	require.True(t, newDebt.AccruedInterest.IsZero())
+	require.True(t, newDebt.Amount.HasDenom("bar"))
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(sdk.NewCoins(
+		sdk.NewCoin("foo", sdk.NewInt(300)), sdk.NewCoin("bar", sdk.NewInt(500)))))
+
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebtInFull("A1", debtor)))
+
+	archived, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.StatusRepaid, archived.Debt.Status)
+	require.True(t, archived.Debt.Settlement.PrincipalPaid.Coins().IsEqual(debt.Amount.Coins()))
+	require.True(t, archived.Debt.Settlement.InterestPaid.Coins().IsEqual(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(50)))))
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(sdk.NewCoins(
+		sdk.NewCoin("foo", sdk.NewInt(1050)), sdk.NewCoin("bar", sdk.NewInt(500)))))
+}
+
+func TestKeeper_ChangeMultiCoinDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	foo := sdk.NewCoin("foo", sdk.NewInt(1000))
+	bar := sdk.NewCoin("bar", sdk.NewInt(500))
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewMultiCoinDebt("A1", debtor, sdk.NewCoins(foo, bar), creditor)))
+
+	// the creditor cannot forgive more than the outstanding amount of any denom
+	err := keeper.ChangeDebt(ctx, types.NewMsgChangeDebtCoins("A1", sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10)), bar.Add(bar)), creditor))
+	require.True(t, types.ErrInvalidAmount.Is(err))
+	err = keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("baz", sdk.NewInt(10)), creditor))
+	require.True(t, types.ErrDenomMismatch.Is(err))
+
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", bar, creditor)))
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.True(t, newDebt.Amount.Coins().IsEqual(sdk.NewCoins(foo)))
+
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", foo, creditor)))
+	archived, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.StatusForgiven, archived.Debt.Status)
+	require.True(t, archived.Debt.Settlement.Forgiven.Coins().IsEqual(sdk.NewCoins(foo, bar)))
+}
+
+func TestKeeper_ForgiveDebtWithInterest(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	foo := func(amount int64) types.DebtCoins {
+		return types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(amount)))
 	}
 
+	_, ctx, _, _, keeper := SetupTestInput()
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+	debt.AccruedInterest = foo(10)
+	debt.AccruedFees = foo(5)
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+	// the creditor cannot forgive more than what is owed
+	err := keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(116)), creditor))
+	require.True(t, types.ErrInvalidAmount.Is(err))
+
+	// forgiveness covers the late fees first, then the interest and then the principal
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.True(t, newDebt.AccruedFees.IsZero())
+	require.True(t, newDebt.AccruedInterest.IsZero())
+	require.Equal(t, foo(15), newDebt.Amount)
+	require.Equal(t, types.StatusActive, newDebt.Status)
+
+	// what is left can be forgiven as well
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(15)), creditor)))
+	archived, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.StatusForgiven, archived.Debt.Status)
+	require.True(t, archived.Debt.Settlement.Forgiven.Coins().IsEqual(foo(115).Coins()))
+}
+
+func TestDebtCoins_SingleCoinJSON(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+
+	// clients of single-denom debts keep sending and receiving a coin object
+	single := types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(10)), debtor)
+	require.Contains(t, string(single.GetSignBytes()), `"amount":{"amount":"10","denom":"foo"}`)
+
+	cdc := codec.New()
+	var msg types.MsgPayDebt
+	cdc.MustUnmarshalJSON([]byte(`{"id":"A1","amount":{"denom":"foo","amount":"10"},"debtor":"`+debtor.String()+`"}`), &msg)
+	require.Equal(t, single.GetSignBytes(), msg.GetSignBytes())
+
+	// many denoms go in an array
+	multi := types.NewMsgPayDebtCoins("A1", sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10)), sdk.NewCoin("bar", sdk.NewInt(5))), debtor)
+	bz := cdc.MustMarshalJSON(multi)
+	require.Contains(t, string(bz), `"amount":[{"denom":"bar","amount":"5"},{"denom":"foo","amount":"10"}]`)
+	cdc.MustUnmarshalJSON(bz, &msg)
+	require.Equal(t, multi.GetSignBytes(), msg.GetSignBytes())
+}
+
+func TestKeeper_GetDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+
 	tests := []struct {
 		name            string
 		ID              string
@@ -270,7 +509,7 @@ func TestKeeper_getDebtByID(t *testing.T) {
 				require.NoError(t, keeper.CreateDebt(ctx, *tt.preExistingDebt))
 			}
 
-			result, err := keeper.getDebtByID(ctx, tt.ID)
+			result, err := keeper.GetDebt(ctx, tt.ID)
 
 			if tt.wantErr {
 				require.Error(t, err)
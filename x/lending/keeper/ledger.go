@@ -0,0 +1,95 @@
+package keeper
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// the ledger of a debt maps its ID and the sequence number of each entry to the entry.
+// IDs are prefixed with their length, so that the entries of a debt are never
+// found under the prefix of another debt whose ID starts with the same characters
+const ledgerStorePrefix = ":ledger:"
+
+func getLedgerPrefix(debtID string) []byte {
+	prefix := append([]byte(ledgerStorePrefix), sdk.Uint64ToBigEndian(uint64(len(debtID)))...)
+	return append(prefix, debtID...)
+}
+
+func getLedgerKey(debtID string, sequence uint64) []byte {
+	return append(getLedgerPrefix(debtID), sdk.Uint64ToBigEndian(sequence)...)
+}
+
+// recordEntry appends to the ledger of the debt an entry for a movement of the given
+// amount, that left the debt as given, in the current block and transaction
+func (keeper Keeper) recordEntry(ctx sdk.Context, entryType types.EntryType, debt types.Debt, amount types.DebtCoins) {
+	var txHash string
+	if len(ctx.TxBytes()) > 0 {
+		txHash = fmt.Sprintf("%X", tmhash.Sum(ctx.TxBytes()))
+	}
+
+	keeper.SetLedgerEntry(ctx, types.LedgerEntry{
+		DebtID:    debt.ID,
+		Sequence:  keeper.lastLedgerSequence(ctx, debt.ID) + 1,
+		Type:      entryType,
+		Amount:    amount,
+		Remaining: debt.Owed(),
+		Creditor:  debt.Creditor,
+		Height:    ctx.BlockHeight(),
+		Time:      ctx.BlockTime(),
+		TxHash:    txHash,
+	})
+}
+
+// lastLedgerSequence yields the sequence number of the last entry of the ledger of the debt, or 0 if it has none
+func (keeper Keeper) lastLedgerSequence(ctx sdk.Context, debtID string) uint64 {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStoreReversePrefixIterator(store, getLedgerPrefix(debtID))
+	defer ri.Close()
+
+	if !ri.Valid() {
+		return 0
+	}
+
+	var entry types.LedgerEntry
+	keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &entry)
+	return entry.Sequence
+}
+
+// SetLedgerEntry stores an entry in the ledger of its debt
+func (keeper Keeper) SetLedgerEntry(ctx sdk.Context, entry types.LedgerEntry) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getLedgerKey(entry.DebtID, entry.Sequence), keeper.cdc.MustMarshalBinaryBare(&entry))
+}
+
+// GetLedger yields the entries of the ledger of the debt, in order
+func (keeper Keeper) GetLedger(ctx sdk.Context, debtID string) []types.LedgerEntry {
+	return keeper.getLedgerEntries(ctx, getLedgerPrefix(debtID))
+}
+
+// GetAllLedgerEntries yields the entries of the ledgers of all debts
+func (keeper Keeper) GetAllLedgerEntries(ctx sdk.Context) []types.LedgerEntry {
+	return keeper.getLedgerEntries(ctx, []byte(ledgerStorePrefix))
+}
+
+func (keeper Keeper) getLedgerEntries(ctx sdk.Context, prefix []byte) []types.LedgerEntry {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, prefix)
+	defer ri.Close()
+
+	entries := []types.LedgerEntry{}
+	for ; ri.Valid(); ri.Next() {
+		var entry types.LedgerEntry
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &entry)
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// deleteLedger deletes all entries of the ledger of the debt
+func (keeper Keeper) deleteLedger(ctx sdk.Context, debtID string) {
+	keeper.clearPrefix(ctx, getLedgerPrefix(debtID))
+}
@@ -0,0 +1,139 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestKeeper_Ledger(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	blockTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(10).WithBlockTime(blockTime)
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10000)))))
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+	debt.LastAccrualHeight = 10
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+	// one period of interest elapses, then the debtor pays inside a transaction
+	ctx = ctx.WithBlockHeight(20)
+	keeper.AccrueInterest(ctx)
+
+	txCtx := ctx.WithTxBytes([]byte("tx"))
+	require.NoError(t, keeper.PayDebt(txCtx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(300)), debtor)))
+	require.NoError(t, keeper.ChangeDebt(txCtx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(100)), creditor)))
+
+	// the ledger survives the closing of the debt
+	require.NoError(t, keeper.PayDebt(txCtx, types.NewMsgPayDebtInFull("A1", debtor)))
+
+	ledger := keeper.GetLedger(ctx, "A1")
+	require.Len(t, ledger, 4)
+
+	expected := []struct {
+		entryType types.EntryType
+		amount    int64
+		remaining int64
+	}{
+		{types.EntryInterest, 10, 1010},
+		{types.EntryPayment, 300, 710},
+		{types.EntryForgiveness, 100, 610},
+		{types.EntryPayment, 610, 0},
+	}
+	for i, entry := range ledger {
+		require.Equal(t, "A1", entry.DebtID)
+		require.Equal(t, uint64(i+1), entry.Sequence)
+		require.Equal(t, expected[i].entryType, entry.Type)
+		require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(expected[i].amount))), entry.Amount)
+		require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(expected[i].remaining))), entry.Remaining)
+		require.Equal(t, int64(20), entry.Height)
+		require.True(t, blockTime.Equal(entry.Time))
+	}
+
+	// interest accrues outside transactions
+	require.Empty(t, ledger[0].TxHash)
+	require.Len(t, ledger[1].TxHash, 64)
+
+	// the ledger of a debt is not mixed with that of a debt whose ID extends its own
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)))
+	require.Empty(t, keeper.GetLedger(ctx, "A"))
+	require.Len(t, keeper.GetAllLedgerEntries(ctx), 4)
+}
+
+func TestKeeper_LedgerSkipsZeroInterest(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(1)
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(10)), creditor)
+	debt.Interest = types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 1, types.PeriodBlocks)
+	debt.LastAccrualHeight = 1
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+	// each block accrues less than a coin
+	for height := int64(2); height <= 5; height++ {
+		keeper.AccrueInterest(ctx.WithBlockHeight(height))
+	}
+
+	require.Empty(t, keeper.GetLedger(ctx, "A1"))
+}
+
+func Test_queryGetDebtLedger(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	tests := []struct {
+		name      string
+		id        string
+		params    types.QueryDebtsParams
+		sequences []uint64
+		wantErr   error
+	}{
+		{"first page", "A1", types.NewQueryDebtsParams(1, 2), []uint64{1, 2}, nil},
+		{"last page", "A1", types.NewQueryDebtsParams(2, 2), []uint64{3}, nil},
+		{"page out of bounds", "A1", types.NewQueryDebtsParams(3, 2), []uint64{}, nil},
+		{"unknown debt", "A2", types.NewQueryDebtsParams(1, 2), nil, types.ErrDebtNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, bankKeeper, keeper := SetupTestInput()
+			require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+			require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)))
+			for i := 0; i < 3; i++ {
+				require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(100)), debtor)))
+			}
+
+			path := []string{types.QueryDebtLedger, tt.id}
+			result, err := NewQuerier(keeper)(ctx, path, abci.RequestQuery{Data: cdc.MustMarshalJSON(tt.params)})
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var entries []types.LedgerEntry
+			cdc.MustUnmarshalJSON(result, &entries)
+
+			sequences := []uint64{}
+			for _, entry := range entries {
+				sequences = append(sequences, entry.Sequence)
+			}
+			require.Equal(t, tt.sequences, sequences)
+		})
+	}
+}
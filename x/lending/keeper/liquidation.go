@@ -0,0 +1,101 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// IsUndercollateralized yields true if the value of the collateral of the debt
+// has fallen below the liquidation ratio of the value it owes
+func (keeper Keeper) IsUndercollateralized(ctx sdk.Context, debt types.Debt) (bool, error) {
+	if debt.Status.IsClosed() || debt.Collateral.Empty() || debt.Owed().IsZero() {
+		return false, nil
+	}
+
+	owedValue, err := keeper.valueOf(ctx, debt.Owed().Coins())
+	if err != nil {
+		return false, err
+	}
+
+	collateralValue, err := keeper.valueOf(ctx, debt.Collateral)
+	if err != nil {
+		return false, err
+	}
+
+	return collateralValue.LT(owedValue.Mul(keeper.GetParams(ctx).LiquidationRatio)), nil
+}
+
+// Liquidate lets the liquidator pay what an under-collateralized debt owes to its creditor.
+// In exchange, the liquidator receives collateral worth the payment plus the liquidation
+// discount, and the rest of the collateral goes back to the debtor
+func (keeper Keeper) Liquidate(ctx sdk.Context, msg types.MsgLiquidate) error {
+	debt, err := keeper.GetDebt(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	undercollateralized, err := keeper.IsUndercollateralized(ctx, debt)
+	if err != nil {
+		return err
+	}
+	if !undercollateralized {
+		return sdkErr.Wrapf(types.ErrNotLiquidatable, "the debt with ID %s cannot be liquidated", msg.ID)
+	}
+
+	owed := debt.Owed()
+	if err := keeper.bankKeeper.SendCoins(ctx, msg.Liquidator, debt.Creditor, owed.Coins()); err != nil {
+		return err
+	}
+
+	owedValue, err := keeper.valueOf(ctx, owed.Coins())
+	if err != nil {
+		return err
+	}
+	collateralValue, err := keeper.valueOf(ctx, debt.Collateral)
+	if err != nil {
+		return err
+	}
+
+	// the share of the collateral that is worth the payment plus the discount
+	discount := keeper.GetParams(ctx).LiquidationDiscount
+	share := owedValue.Quo(collateralValue.Mul(sdk.OneDec().Sub(discount)))
+	if share.GT(sdk.OneDec()) {
+		share = sdk.OneDec()
+	}
+
+	seized := sdk.NewCoins()
+	for _, coin := range debt.Collateral {
+		seized = seized.Add(sdk.NewCoin(coin.Denom, share.MulInt(coin.Amount).TruncateInt()))
+	}
+	remainder := debt.Collateral.Sub(seized)
+
+	if !seized.Empty() {
+		if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, msg.Liquidator, seized); err != nil {
+			return err
+		}
+	}
+	if !remainder.Empty() {
+		if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, debt.Debtor, remainder); err != nil {
+			return err
+		}
+	}
+
+	// the creditor is paid in full, although by the liquidator
+	debt.Settlement = debt.Settlement.AddPaid(debt.Amount, debt.AccruedInterest).AddFeesPaid(debt.AccruedFees)
+	debt.Schedule = debt.Schedule.Allocate(debt.Amount.Add(debt.AccruedInterest))
+	debt.Amount = debt.Amount.Zero()
+	debt.AccruedInterest = debt.AccruedInterest.Zero()
+	debt.AccruedFees = debt.AccruedFees.Zero()
+	debt.Collateral = sdk.NewCoins()
+	debt.Status = types.StatusLiquidated
+
+	if err := keeper.closeDebt(ctx, debt); err != nil {
+		return err
+	}
+
+	keeper.recordEntry(ctx, types.EntryPayment, debt, owed)
+	keeper.emitDebtEvent(ctx, types.ActionLiquidate, debt, owed.String(),
+		sdk.NewAttribute(types.AttributeKeyLiquidator, msg.Liquidator.String()))
+	return nil
+}
@@ -0,0 +1,106 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_Liquidate(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	liquidator := sdk.AccAddress([]byte("liquidator__________"))
+	oracle := sdk.AccAddress([]byte("oracle______________"))
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+	collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(1500)))
+
+	tests := []struct {
+		name              string
+		collateralPrice   string
+		expectedSeized    sdk.Coins
+		expectedRemainder sdk.Coins
+		wantErr           bool
+	}{
+		{
+			"sufficiently collateralized debt",
+			"1.0",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"under-collateralized debt",
+			"0.9",
+			sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(1169))),
+			sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(331))),
+			false,
+		},
+		{
+			"collateral worth less than the debt",
+			"0.5",
+			collateral,
+			sdk.NewCoins(),
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+			params := types.DefaultParams()
+			params.Oracles = []sdk.AccAddress{oracle}
+			keeper.SetParams(ctx, params)
+
+			require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.OneDec())))
+			require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "bar", sdk.MustNewDecFromStr(tt.collateralPrice))))
+
+			debt := types.NewDebt("A1", debtor, amount, creditor)
+			debt.Collateral = collateral
+			require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount)))
+			require.NoError(t, bankKeeper.SetCoins(ctx, debtor, collateral))
+			require.NoError(t, keeper.DisburseDebt(ctx, debt))
+			require.NoError(t, bankKeeper.SetCoins(ctx, liquidator, sdk.NewCoins(amount)))
+
+			err := keeper.Liquidate(ctx, types.NewMsgLiquidate(debt.ID, liquidator))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, keeper.GetModuleAccountCoins(ctx).IsEqual(collateral))
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, authKeeper.GetAccount(ctx, liquidator).GetCoins().IsEqual(tt.expectedSeized))
+			require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(sdk.NewCoins(amount)))
+			require.True(t, authKeeper.GetAccount(ctx, debtor).GetCoins().IsEqual(tt.expectedRemainder.Add(amount)))
+			require.True(t, keeper.GetModuleAccountCoins(ctx).Empty())
+
+			archived, err := keeper.GetArchivedDebt(ctx, debt.ID)
+			require.NoError(t, err)
+			require.Equal(t, types.StatusLiquidated, archived.Debt.Status)
+			require.True(t, archived.Debt.Owed().IsZero())
+
+			_, broken := LockedCoinsAreHeld(keeper)(ctx)
+			require.False(t, broken)
+
+			// a liquidated debt cannot be liquidated again
+			require.Error(t, keeper.Liquidate(ctx, types.NewMsgLiquidate(debt.ID, liquidator)))
+		})
+	}
+}
+
+func TestKeeper_LiquidateWithoutPrice(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	liquidator := sdk.AccAddress([]byte("liquidator__________"))
+
+	_, ctx, _, _, keeper := SetupTestInput()
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Collateral = sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(1500)))
+	require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+	require.Error(t, keeper.Liquidate(ctx, types.NewMsgLiquidate(debt.ID, liquidator)))
+}
@@ -1,22 +1,94 @@
 package keeper
-/*
-// TODO: Define if your module needs Parameters, if not this can be deleted
 
 import (
-	"time"
-
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/spoto/lending/x/lending/types"
 )
 
 // GetParams returns the total set of lending parameters.
-func (k Keeper) GetParams(ctx sdk.Context) (params types.Params) {
-	k.paramspace.GetParamSet(ctx, &params)
+func (keeper Keeper) GetParams(ctx sdk.Context) (params types.Params) {
+	keeper.paramspace.GetParamSet(ctx, &params)
 	return params
 }
 
 // SetParams sets the lending parameters to the param space.
-func (k Keeper) SetParams(ctx sdk.Context, params types.Params) {
-	k.paramspace.SetParamSet(ctx, &params)
+func (keeper Keeper) SetParams(ctx sdk.Context, params types.Params) {
+	keeper.paramspace.SetParamSet(ctx, &params)
+}
+
+// applyParams checks a new debt against the lending rules of the parameters,
+// and fills in the terms that the parameters provide by default
+func (keeper Keeper) applyParams(ctx sdk.Context, debt *types.Debt) error {
+	params := keeper.GetParams(ctx)
+
+	for _, coin := range debt.Amount {
+		if err := checkAmount(params, coin); err != nil {
+			return err
+		}
+	}
+
+	if err := checkInterest(params, debt.Interest); err != nil {
+		return err
+	}
+
+	if debt.LateFees.HasRate() && debt.LateFees.Rate.GT(params.MaxLateFeeRate) {
+		return sdkErr.Wrapf(types.ErrLateFeeTooHigh, "late fee rate %s exceeds the maximum %s", debt.LateFees.Rate, params.MaxLateFeeRate)
+	}
+
+	if debt.LateFees.HasPenaltyRate() {
+		penalty := debt.Interest
+		penalty.Rate = debt.LateFees.PenaltyRate
+		if yield := penalty.AnnualYield(params.BlocksPerYear); yield.GT(params.MaxPenaltyRate) {
+			return sdkErr.Wrapf(types.ErrInterestRateTooHigh, "penalty interest of %s yields %s a year, beyond the maximum %s",
+				penalty, yield, params.MaxPenaltyRate)
+		}
+	}
+
+	if count := debt.Repayment.InstallmentCount(); count > params.MaxInstallments {
+		return sdkErr.Wrapf(types.ErrTooManyInstallments, "the schedule has %d installments, beyond the maximum %d",
+			count, params.MaxInstallments)
+	}
+
+	if debt.HasMaturity() && debt.GracePeriod == 0 {
+		debt.GracePeriod = params.DefaultGracePeriod
+	}
+
+	return nil
+}
+
+// applyCreditLineParams checks a new credit line against the lending rules of the
+// parameters. Its limit is subject to the same caps as the amount of a debt
+func (keeper Keeper) applyCreditLineParams(ctx sdk.Context, line types.CreditLine) error {
+	params := keeper.GetParams(ctx)
+
+	if err := checkAmount(params, sdk.NewCoin(line.Denom, line.Limit)); err != nil {
+		return err
+	}
+
+	return checkInterest(params, line.Interest)
+}
+
+// checkAmount yields an error if the params do not allow lending the given coin
+func checkAmount(params types.Params, coin sdk.Coin) error {
+	if !params.IsAllowedDenom(coin.Denom) {
+		return sdkErr.Wrapf(types.ErrDenomNotAllowed, "debts in %s are not allowed", coin.Denom)
+	}
+
+	if max := params.MaxDebtAmounts.AmountOf(coin.Denom); max.IsPositive() && coin.Amount.GT(max) {
+		return sdkErr.Wrapf(types.ErrAmountTooLarge, "debts in %s cannot exceed %s%s", coin.Denom, max, coin.Denom)
+	}
+
+	return nil
+}
+
+// checkInterest yields an error if the given interest terms yield more in a year than the params allow,
+// whatever their period, so that no debt can grow fast enough to overflow
+func checkInterest(params types.Params, interest types.InterestTerms) error {
+	if yield := interest.AnnualYield(params.BlocksPerYear); yield.GT(params.MaxInterestRate) {
+		return sdkErr.Wrapf(types.ErrInterestRateTooHigh, "interest of %s yields %s a year, beyond the maximum %s",
+			interest, yield, params.MaxInterestRate)
+	}
+
+	return nil
 }
-*/
@@ -0,0 +1,153 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_ParamsAreEnforced(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	params := types.DefaultParams()
+	params.AllowedDenoms = []string{"bar", "foo"}
+	params.MaxDebtAmounts = sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))
+	params.MinProposalExpiry = 10
+	params.MaxInterestRate = sdk.NewDecWithPrec(1, 1)
+
+	yearly := func(rate sdk.Dec) types.InterestTerms {
+		return types.NewInterestTerms(rate, types.InterestSimple, types.DefaultBlocksPerYear, types.PeriodBlocks)
+	}
+
+	tests := []struct {
+		name     string
+		amount   sdk.Coin
+		interest types.InterestTerms
+		expiry   int64
+		wantErr  bool
+	}{
+		{"debt within the rules", sdk.NewCoin("foo", sdk.NewInt(1000)), yearly(sdk.NewDecWithPrec(1, 1)), 10, false},
+		{"denom without cap", sdk.NewCoin("bar", sdk.NewInt(100000)), types.NoInterest(), 0, false},
+		{"denom not allowed", sdk.NewCoin("baz", sdk.NewInt(100)), types.NoInterest(), 0, true},
+		{"amount over the cap", sdk.NewCoin("foo", sdk.NewInt(1001)), types.NoInterest(), 0, true},
+		{"interest rate over the maximum", sdk.NewCoin("foo", sdk.NewInt(100)), yearly(sdk.NewDecWithPrec(2, 1)), 0, true},
+		{"small rate compounding at each block", sdk.NewCoin("foo", sdk.NewInt(100)),
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 6), types.InterestCompound, 1, types.PeriodBlocks), 0, true},
+		{"whole rate compounding at each block", sdk.NewCoin("foo", sdk.NewInt(100)),
+			types.NewInterestTerms(sdk.OneDec(), types.InterestCompound, 1, types.PeriodBlocks), 0, true},
+		{"expiry under the minimum", sdk.NewCoin("foo", sdk.NewInt(100)), types.NoInterest(), 9, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			keeper.SetParams(ctx, params)
+			require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(tt.amount)))
+
+			debt := types.NewDebt("A1", debtor, tt.amount, creditor)
+			debt.Interest = tt.interest
+
+			err := keeper.ProposeDebt(ctx, debt, tt.expiry)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Empty(t, keeper.GetAllProposals(ctx))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, keeper.GetAllProposals(ctx), 1)
+		})
+	}
+}
+
+func TestKeeper_MaxInstallments(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+	due := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	foo := func(amount int64) sdk.Coins { return sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(amount))) }
+
+	tests := []struct {
+		name      string
+		repayment types.ScheduleTerms
+		wantErr   bool
+	}{
+		{"installments within the maximum", types.NewScheduleTerms(types.ScheduleEqual, 2, time.Hour), false},
+		{"installments beyond the maximum", types.NewScheduleTerms(types.ScheduleEqual, 3, time.Hour), true},
+		{"custom installments beyond the maximum", types.NewCustomScheduleTerms(
+			types.NewInstallmentDue(due, foo(300)),
+			types.NewInstallmentDue(due.Add(time.Hour), foo(300)),
+			types.NewInstallmentDue(due.Add(2*time.Hour), foo(400)),
+		), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			params := types.DefaultParams()
+			params.MaxInstallments = 2
+			keeper.SetParams(ctx, params)
+			require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount)))
+
+			debt := types.NewDebt("A1", debtor, amount, creditor)
+			debt.Repayment = tt.repayment
+
+			err := keeper.ProposeDebt(ctx, debt, 0)
+			if tt.wantErr {
+				require.True(t, errors.Is(err, types.ErrTooManyInstallments), err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestKeeper_DefaultGracePeriod(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+	maturity := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(amount.Add(amount))))
+
+	withoutGrace := types.NewDebt("A1", debtor, amount, creditor)
+	withoutGrace.MaturityTime = maturity
+	require.NoError(t, keeper.DisburseDebt(ctx, withoutGrace))
+
+	withGrace := types.NewDebt("A2", debtor, amount, creditor)
+	withGrace.MaturityTime = maturity
+	withGrace.GracePeriod = time.Hour
+	require.NoError(t, keeper.DisburseDebt(ctx, withGrace))
+
+	debt, err := keeper.GetDebt(ctx, withoutGrace.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.DefaultDefaultGracePeriod, debt.GracePeriod)
+
+	debt, err = keeper.GetDebt(ctx, withGrace.ID)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, debt.GracePeriod)
+}
+
+func Test_queryGetParams(t *testing.T) {
+	cdc, ctx, _, _, keeper := SetupTestInput()
+
+	params := types.DefaultParams()
+	params.AllowedDenoms = []string{"foo"}
+	keeper.SetParams(ctx, params)
+
+	result, err := queryGetParams(ctx, keeper)
+	require.NoError(t, err)
+
+	var p types.Params
+	require.NotPanics(t, func() {
+		cdc.MustUnmarshalJSON(result, &p)
+	})
+	require.Equal(t, params.String(), p.String())
+}
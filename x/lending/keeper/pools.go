@@ -0,0 +1,300 @@
+package keeper
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// there is at most a pool for each denom
+const poolStorePrefix = ":pool:"
+
+func getPoolStoreKey(denom string) []byte {
+	return []byte(poolStorePrefix + denom)
+}
+
+// the loans of each pool map denom|id to nothing. They are written only when the
+// pool lends, so that debts passed to the pool otherwise do not count as lent
+const poolLoanIndexPrefix = ":poolloan:"
+
+func getPoolLoanIndexPrefix(denom string) []byte {
+	return []byte(poolLoanIndexPrefix + denom + "|")
+}
+
+// SetPoolLoan records that a debt was drawn from the pool of a denom
+func (keeper Keeper) SetPoolLoan(ctx sdk.Context, loan types.PoolLoan) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(append(getPoolLoanIndexPrefix(loan.Denom), loan.DebtID...), []byte{})
+}
+
+// deletePoolLoan forgets a debt drawn from a pool, once it is closed
+func (keeper Keeper) deletePoolLoan(ctx sdk.Context, debt types.Debt) {
+	store := ctx.KVStore(keeper.storeKey)
+	for _, coin := range debt.Amount {
+		store.Delete(append(getPoolLoanIndexPrefix(coin.Denom), debt.ID...))
+	}
+}
+
+// getPoolLoans yields the open debts drawn from the pool, still owed to it
+func (keeper Keeper) getPoolLoans(ctx sdk.Context, pool types.Pool) []types.Debt {
+	loans := []types.Debt{}
+	for _, debt := range keeper.getIndexedDebts(ctx, getPoolLoanIndexPrefix(pool.Denom)) {
+		if debt.Creditor.Equals(pool.Address()) {
+			loans = append(loans, debt)
+		}
+	}
+
+	return loans
+}
+
+func (keeper Keeper) GetAllPoolLoans(ctx sdk.Context) []types.PoolLoan {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, []byte(poolLoanIndexPrefix))
+	defer ri.Close()
+
+	loans := []types.PoolLoan{}
+	for ; ri.Valid(); ri.Next() {
+		key := string(ri.Key()[len(poolLoanIndexPrefix):])
+		separator := strings.Index(key, "|")
+		loans = append(loans, types.NewPoolLoan(key[:separator], key[separator+1:]))
+	}
+
+	return loans
+}
+
+// GetPool yields the pool of the given denom
+func (keeper Keeper) GetPool(ctx sdk.Context, denom string) (types.Pool, error) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	poolKey := getPoolStoreKey(denom)
+	if !store.Has(poolKey) {
+		return types.Pool{}, sdkErr.Wrapf(types.ErrPoolNotFound, "there is no pool for %s", denom)
+	}
+
+	var pool types.Pool
+	keeper.cdc.MustUnmarshalBinaryBare(store.Get(poolKey), &pool)
+	return pool, nil
+}
+
+// SetPool stores a pool, without moving any coin
+func (keeper Keeper) SetPool(ctx sdk.Context, pool types.Pool) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getPoolStoreKey(pool.Denom), keeper.cdc.MustMarshalBinaryBare(&pool))
+}
+
+// CreatePool creates the pool of a denom, with no deposits yet. Only the pool creators
+// of the params can create pools, since the terms of a pool cannot change afterwards
+func (keeper Keeper) CreatePool(ctx sdk.Context, msg types.MsgCreatePool) error {
+	params := keeper.GetParams(ctx)
+	if !params.IsPoolCreator(msg.Creator) {
+		return sdkErr.Wrap(types.ErrNotPoolCreator, msg.Creator.String())
+	}
+
+	store := ctx.KVStore(keeper.storeKey)
+	if store.Has(getPoolStoreKey(msg.Denom)) {
+		return sdkErr.Wrapf(types.ErrPoolExists, "there is already a pool for %s", msg.Denom)
+	}
+
+	// pools that do not specify interest lend interest-free
+	pool := types.NewPool(msg.Denom, msg.Interest)
+	if pool.Interest.Rate.IsNil() {
+		pool.Interest = types.NoInterest()
+	}
+
+	if !params.IsAllowedDenom(pool.Denom) {
+		return sdkErr.Wrapf(types.ErrDenomNotAllowed, "debts in %s are not allowed", pool.Denom)
+	}
+	if err := checkInterest(params, pool.Interest); err != nil {
+		return err
+	}
+
+	// the rate of pools with a rate model follows their utilization instead
+	if _, ok := params.RateModel(pool.Denom); ok {
+		pool.Interest = poolRates(params, keeper.poolState(ctx, pool)).Interest
+	}
+
+	pool.TotalShares = sdk.ZeroInt()
+	keeper.SetPool(ctx, pool)
+
+	keeper.emitPoolEvent(ctx, types.ActionCreatePool, pool, "")
+	return nil
+}
+
+// GetPoolState yields the pool of the given denom, with its liquidity and what is owed to it
+func (keeper Keeper) GetPoolState(ctx sdk.Context, denom string) (types.PoolState, error) {
+	pool, err := keeper.GetPool(ctx, denom)
+	if err != nil {
+		return types.PoolState{}, err
+	}
+
+	return keeper.poolState(ctx, pool), nil
+}
+
+func (keeper Keeper) poolState(ctx sdk.Context, pool types.Pool) types.PoolState {
+	liquidity := keeper.bankKeeper.GetCoins(ctx, pool.Address()).AmountOf(pool.Denom)
+
+	borrowed := sdk.ZeroInt()
+	for _, debt := range keeper.getPoolLoans(ctx, pool) {
+		borrowed = borrowed.Add(debt.Owed().AmountOf(pool.Denom))
+	}
+
+	return types.NewPoolState(pool, liquidity, borrowed)
+}
+
+// DepositToPool moves an amount from the depositor into the pool of its denom and
+// mints for the depositor the share tokens that the amount is worth
+func (keeper Keeper) DepositToPool(ctx sdk.Context, msg types.MsgDepositToPool) error {
+	state, err := keeper.GetPoolState(ctx, msg.Amount.Denom)
+	if err != nil {
+		return err
+	}
+
+	shares := state.SharesFor(msg.Amount.Amount)
+	if !shares.IsPositive() {
+		return sdkErr.Wrapf(types.ErrInvalidAmount, "a deposit of %s is not worth any share of the pool", msg.Amount)
+	}
+
+	pool := state.Pool
+	if err := keeper.bankKeeper.SendCoins(ctx, msg.Depositor, pool.Address(), sdk.NewCoins(msg.Amount)); err != nil {
+		return err
+	}
+
+	minted := sdk.NewCoins(sdk.NewCoin(pool.ShareDenom(), shares))
+	if err := keeper.supplyKeeper.MintCoins(ctx, types.ModuleName, minted); err != nil {
+		return err
+	}
+	if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, msg.Depositor, minted); err != nil {
+		return err
+	}
+
+	pool.TotalShares = pool.TotalShares.Add(shares)
+	keeper.SetPool(ctx, pool)
+
+	keeper.emitPoolEvent(ctx, types.ActionDeposit, pool, msg.Amount.String(),
+		sdk.NewAttribute(types.AttributeKeyDepositor, msg.Depositor.String()),
+		sdk.NewAttribute(types.AttributeKeyShares, minted.String()))
+	return nil
+}
+
+// WithdrawFromPool burns share tokens of the depositor and gives back what they are
+// worth, as long as the pool holds enough liquidity, since what is lent stays owed
+func (keeper Keeper) WithdrawFromPool(ctx sdk.Context, msg types.MsgWithdrawFromPool) error {
+	state, err := keeper.GetPoolState(ctx, msg.Denom)
+	if err != nil {
+		return err
+	}
+
+	amount := state.AmountFor(msg.Shares)
+	if amount.GT(state.Liquidity) {
+		return sdkErr.Wrapf(types.ErrInsufficientLiquidity, "the pool for %s holds only %s%s, while the shares are worth %s%s",
+			msg.Denom, state.Liquidity, msg.Denom, amount, msg.Denom)
+	}
+
+	pool := state.Pool
+	burnt := sdk.NewCoins(sdk.NewCoin(pool.ShareDenom(), msg.Shares))
+	if err := keeper.supplyKeeper.SendCoinsFromAccountToModule(ctx, msg.Depositor, types.ModuleName, burnt); err != nil {
+		return err
+	}
+	if err := keeper.supplyKeeper.BurnCoins(ctx, types.ModuleName, burnt); err != nil {
+		return err
+	}
+
+	withdrawn := sdk.NewCoin(pool.Denom, amount)
+	if err := keeper.bankKeeper.SendCoins(ctx, pool.Address(), msg.Depositor, sdk.NewCoins(withdrawn)); err != nil {
+		return err
+	}
+
+	pool.TotalShares = pool.TotalShares.Sub(msg.Shares)
+	keeper.SetPool(ctx, pool)
+
+	keeper.emitPoolEvent(ctx, types.ActionWithdrawDeposit, pool, withdrawn.String(),
+		sdk.NewAttribute(types.AttributeKeyDepositor, msg.Depositor.String()),
+		sdk.NewAttribute(types.AttributeKeyShares, burnt.String()))
+	return nil
+}
+
+// BorrowFromPool disburses a loan from the pool of the denom of its amount, as a debt
+// whose creditor is the pool, that accrues interest with the terms of the pool.
+// The collateral must be worth at least the liquidation ratio of the loan
+func (keeper Keeper) BorrowFromPool(ctx sdk.Context, msg types.MsgBorrowFromPool) error {
+	state, err := keeper.GetPoolState(ctx, msg.Amount.Denom)
+	if err != nil {
+		return err
+	}
+
+	if msg.Amount.Amount.GT(state.Liquidity) {
+		return sdkErr.Wrapf(types.ErrInsufficientLiquidity, "the pool for %s can only lend %s%s",
+			msg.Amount.Denom, state.Liquidity, msg.Amount.Denom)
+	}
+
+	amountValue, err := keeper.valueOf(ctx, sdk.NewCoins(msg.Amount))
+	if err != nil {
+		return err
+	}
+	collateralValue, err := keeper.valueOf(ctx, msg.Collateral)
+	if err != nil {
+		return err
+	}
+	if ratio := keeper.GetParams(ctx).LiquidationRatio; collateralValue.LT(amountValue.Mul(ratio)) {
+		return sdkErr.Wrapf(types.ErrUndercollateralized, "the collateral %s must be worth at least %s times the loan", msg.Collateral, ratio)
+	}
+
+	pool := state.Pool
+	debt := types.NewDebt(msg.ID, msg.Borrower, msg.Amount, pool.Address())
+	debt.Interest = pool.Interest
+	debt.Collateral = msg.Collateral
+	if err := keeper.DisburseDebt(ctx, debt); err != nil {
+		return err
+	}
+	keeper.SetPoolLoan(ctx, types.NewPoolLoan(pool.Denom, debt.ID))
+
+	keeper.emitPoolEvent(ctx, types.ActionBorrow, pool, msg.Amount.String(),
+		sdk.NewAttribute(types.AttributeKeyBorrower, msg.Borrower.String()),
+		sdk.NewAttribute(types.AttributeKeyDebtID, msg.ID))
+	return nil
+}
+
+func (keeper Keeper) poolsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(keeper.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(poolStorePrefix))
+}
+
+func (keeper Keeper) GetAllPools(ctx sdk.Context) []types.Pool {
+	ri := keeper.poolsIterator(ctx)
+	defer ri.Close()
+
+	pools := []types.Pool{}
+	for ; ri.Valid(); ri.Next() {
+		var pool types.Pool
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &pool)
+		pools = append(pools, pool)
+	}
+
+	return pools
+}
+
+// GetAllPoolStates yields all pools, with their liquidity and what is owed to them
+func (keeper Keeper) GetAllPoolStates(ctx sdk.Context) []types.PoolState {
+	states := []types.PoolState{}
+	for _, pool := range keeper.GetAllPools(ctx) {
+		states = append(states, keeper.poolState(ctx, pool))
+	}
+
+	return states
+}
+
+// GetPoolDeposits yields the positions of the given address in the pools whose shares it holds
+func (keeper Keeper) GetPoolDeposits(ctx sdk.Context, address sdk.AccAddress) []types.PoolDeposit {
+	coins := keeper.bankKeeper.GetCoins(ctx, address)
+
+	deposits := []types.PoolDeposit{}
+	for _, pool := range keeper.GetAllPools(ctx) {
+		if shares := coins.AmountOf(pool.ShareDenom()); shares.IsPositive() {
+			deposits = append(deposits, types.NewPoolDeposit(address, keeper.poolState(ctx, pool), shares))
+		}
+	}
+
+	return deposits
+}
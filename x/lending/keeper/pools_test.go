@@ -0,0 +1,175 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_DepositAndWithdraw(t *testing.T) {
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	params := types.DefaultParams()
+	params.PoolCreators = []sdk.AccAddress{creator}
+	keeper.SetParams(ctx, params)
+	require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+	require.NoError(t, bankKeeper.SetCoins(ctx, other, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(500)))))
+
+	// there is no pool to deposit into yet
+	err := keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(1000))))
+	require.True(t, errors.Is(err, types.ErrPoolNotFound), err)
+
+	// only the pool creators of the params create pools
+	err = keeper.CreatePool(ctx, types.NewMsgCreatePool(lender, "foo", types.NoInterest()))
+	require.True(t, errors.Is(err, types.ErrNotPoolCreator), err)
+
+	require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", types.NoInterest())))
+	err = keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", types.NoInterest()))
+	require.True(t, errors.Is(err, types.ErrPoolExists), err)
+
+	// a coin is worth a thousand shares
+	require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+	require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(other, sdk.NewCoin("foo", sdk.NewInt(500)))))
+	require.Equal(t, "1000000lpfoo", bankKeeper.GetCoins(ctx, lender).String())
+	require.Equal(t, "500000lpfoo", bankKeeper.GetCoins(ctx, other).String())
+	require.Equal(t, "1500foo", bankKeeper.GetCoins(ctx, types.PoolAddress("foo")).String())
+
+	// shares cannot be withdrawn by who does not hold them
+	require.Error(t, keeper.WithdrawFromPool(ctx, types.NewMsgWithdrawFromPool(other, "foo", sdk.NewInt(500001))))
+
+	require.NoError(t, keeper.WithdrawFromPool(ctx, types.NewMsgWithdrawFromPool(lender, "foo", sdk.NewInt(400000))))
+	require.Equal(t, "400foo,600000lpfoo", bankKeeper.GetCoins(ctx, lender).String())
+
+	state, err := keeper.GetPoolState(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt(1100000), state.Pool.TotalShares)
+	require.Equal(t, sdk.NewInt(1100), state.Liquidity)
+	require.Equal(t, sdk.NewDecWithPrec(1, 3), state.ShareValue)
+	require.Equal(t, sdk.ZeroDec(), state.Utilization)
+
+	_, broken := PoolSharesAreMinted(keeper)(ctx)
+	require.False(t, broken)
+}
+
+func TestKeeper_DonationDoesNotInflateShares(t *testing.T) {
+	attacker, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	victim, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	params := types.DefaultParams()
+	params.PoolCreators = []sdk.AccAddress{creator}
+	keeper.SetParams(ctx, params)
+	require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", types.NoInterest())))
+	require.NoError(t, bankKeeper.SetCoins(ctx, attacker, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1001)))))
+	require.NoError(t, bankKeeper.SetCoins(ctx, victim, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	// the first depositor deposits a coin, then donates to the pool account
+	require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(attacker, sdk.NewCoin("foo", sdk.NewInt(1)))))
+	require.NoError(t, bankKeeper.SendCoins(ctx, attacker, types.PoolAddress("foo"), sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	// the next deposit still gets its shares, and the donation is lost to the attacker
+	require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(victim, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	deposits := keeper.GetPoolDeposits(ctx, victim)
+	require.Len(t, deposits, 1)
+	require.Equal(t, sdk.NewInt(999), deposits[0].Value)
+
+	deposits = keeper.GetPoolDeposits(ctx, attacker)
+	require.Len(t, deposits, 1)
+	require.True(t, deposits[0].Value.LT(sdk.NewInt(1001)))
+}
+
+func TestKeeper_BorrowFromPool(t *testing.T) {
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	oracle := sdk.AccAddress([]byte("oracle______________"))
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	tests := []struct {
+		name       string
+		amount     int64
+		collateral int64
+		wantErr    error
+	}{
+		{"collateralized loan", 600, 900, nil},
+		{"under-collateralized loan", 600, 899, types.ErrUndercollateralized},
+		{"loan beyond the liquidity", 1001, 2000, types.ErrInsufficientLiquidity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			ctx = ctx.WithBlockHeight(1)
+			params := types.DefaultParams()
+			params.Oracles = []sdk.AccAddress{oracle}
+			params.PoolCreators = []sdk.AccAddress{creator}
+			params.BlocksPerYear = 100 // a year of ten interest periods, within the max annual yield
+			keeper.SetParams(ctx, params)
+			require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.OneDec())))
+			require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "bar", sdk.OneDec())))
+
+			interest := types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks)
+			require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", interest)))
+			require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+			require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+			collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(tt.collateral)))
+			require.NoError(t, bankKeeper.SetCoins(ctx, borrower, collateral))
+
+			amount := sdk.NewCoin("foo", sdk.NewInt(tt.amount))
+			err := keeper.BorrowFromPool(ctx, types.NewMsgBorrowFromPool("P1", borrower, amount, collateral))
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				require.Equal(t, "1000foo", bankKeeper.GetCoins(ctx, types.PoolAddress("foo")).String())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "600foo", bankKeeper.GetCoins(ctx, borrower).String())
+
+			debt, err := keeper.GetDebt(ctx, "P1")
+			require.NoError(t, err)
+			require.Equal(t, types.PoolAddress("foo"), debt.Creditor)
+			require.Equal(t, interest, debt.Interest)
+
+			state, err := keeper.GetPoolState(ctx, "foo")
+			require.NoError(t, err)
+			require.Equal(t, sdk.NewInt(400), state.Liquidity)
+			require.Equal(t, sdk.NewInt(600), state.Borrowed)
+			require.Equal(t, sdk.NewDecWithPrec(6, 1), state.Utilization)
+
+			// what is lent cannot be withdrawn
+			err = keeper.WithdrawFromPool(ctx, types.NewMsgWithdrawFromPool(lender, "foo", sdk.NewInt(1000000)))
+			require.True(t, errors.Is(err, types.ErrInsufficientLiquidity), err)
+
+			// debts owed to the pool that it did not lend do not count as lent
+			inflated := types.NewDebt("X1", borrower, sdk.NewCoin("foo", sdk.NewInt(5000)), types.PoolAddress("foo"))
+			require.NoError(t, keeper.CreateDebt(ctx, inflated))
+			state, err = keeper.GetPoolState(ctx, "foo")
+			require.NoError(t, err)
+			require.Equal(t, sdk.NewInt(600), state.Borrowed)
+
+			// the interest owed by the borrower raises the value of the shares,
+			// except for the fraction of coin that goes to the virtual shares
+			keeper.AccrueInterest(ctx.WithBlockHeight(11))
+
+			deposits := keeper.GetPoolDeposits(ctx, lender)
+			require.Len(t, deposits, 1)
+			require.Equal(t, sdk.NewInt(1005), deposits[0].Value)
+			require.Equal(t, sdk.NewInt(400), deposits[0].Available)
+
+			state, err = keeper.GetPoolState(ctx, "foo")
+			require.NoError(t, err)
+			require.Equal(t, sdk.NewInt(1006), state.Value())
+			require.Equal(t, sdk.NewDec(1007).QuoInt64(1001000), state.ShareValue)
+			require.Equal(t, sdk.NewInt(1001000), state.SharesFor(sdk.NewInt(1007)))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package keeper
+
+import (
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+const priceStorePrefix = ":price:"
+
+func getPriceStorePrefix(denom string) []byte {
+	return []byte(priceStorePrefix + denom + ":")
+}
+
+func getPriceStoreKey(denom string, oracle sdk.AccAddress) []byte {
+	return append(getPriceStorePrefix(denom), []byte(oracle.String())...)
+}
+
+// PostPrice records the price of a denom reported by a whitelisted oracle,
+// replacing the previous price reported by the same oracle
+func (keeper Keeper) PostPrice(ctx sdk.Context, msg types.MsgPostPrice) error {
+	if !keeper.GetParams(ctx).IsOracle(msg.Oracle) {
+		return sdkErr.Wrap(types.ErrNotOracle, msg.Oracle.String())
+	}
+
+	keeper.SetPostedPrice(ctx, types.NewPostedPrice(msg.Denom, msg.Oracle, msg.Price, ctx.BlockTime()))
+	return nil
+}
+
+// SetPostedPrice stores the price reported by an oracle, without checking the whitelist
+func (keeper Keeper) SetPostedPrice(ctx sdk.Context, price types.PostedPrice) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getPriceStoreKey(price.Denom, price.Oracle), keeper.cdc.MustMarshalBinaryBare(&price))
+}
+
+// GetPostedPrices yields the prices of a denom reported by the oracles, stale or not
+func (keeper Keeper) GetPostedPrices(ctx sdk.Context, denom string) []types.PostedPrice {
+	return keeper.getPostedPrices(ctx, getPriceStorePrefix(denom))
+}
+
+// GetAllPostedPrices yields the prices of all denoms reported by the oracles, stale or not
+func (keeper Keeper) GetAllPostedPrices(ctx sdk.Context) []types.PostedPrice {
+	return keeper.getPostedPrices(ctx, []byte(priceStorePrefix))
+}
+
+func (keeper Keeper) getPostedPrices(ctx sdk.Context, prefix []byte) []types.PostedPrice {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, prefix)
+	defer ri.Close()
+
+	prices := []types.PostedPrice{}
+	for ; ri.Valid(); ri.Next() {
+		var price types.PostedPrice
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &price)
+		prices = append(prices, price)
+	}
+
+	return prices
+}
+
+// GetCurrentPrice yields the median of the prices of a denom that have been
+// reported recently by the oracles that are still whitelisted
+func (keeper Keeper) GetCurrentPrice(ctx sdk.Context, denom string) (types.CurrentPrice, error) {
+	params := keeper.GetParams(ctx)
+
+	var prices []sdk.Dec
+	for _, price := range keeper.GetPostedPrices(ctx, denom) {
+		if params.IsOracle(price.Oracle) && !price.IsStale(ctx.BlockTime(), params.MaxPriceAge) {
+			prices = append(prices, price.Price)
+		}
+	}
+
+	if len(prices) == 0 {
+		return types.CurrentPrice{}, sdkErr.Wrapf(types.ErrPriceNotFound, "no fresh price available for %s", denom)
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LT(prices[j]) })
+
+	median := prices[len(prices)/2]
+	if len(prices)%2 == 0 {
+		median = median.Add(prices[len(prices)/2-1]).QuoInt64(2)
+	}
+
+	return types.NewCurrentPrice(denom, median), nil
+}
+
+// valueOf yields the value of the given coins, in the reference unit of the prices
+func (keeper Keeper) valueOf(ctx sdk.Context, coins sdk.Coins) (sdk.Dec, error) {
+	value := sdk.ZeroDec()
+
+	for _, coin := range coins {
+		price, err := keeper.GetCurrentPrice(ctx, coin.Denom)
+		if err != nil {
+			return sdk.Dec{}, err
+		}
+
+		value = value.Add(price.Price.MulInt(coin.Amount))
+	}
+
+	return value, nil
+}
@@ -0,0 +1,108 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_GetCurrentPrice(t *testing.T) {
+	oracles := []sdk.AccAddress{
+		sdk.AccAddress([]byte("oracle1_____________")),
+		sdk.AccAddress([]byte("oracle2_____________")),
+		sdk.AccAddress([]byte("oracle3_____________")),
+	}
+	stranger := sdk.AccAddress([]byte("stranger____________"))
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		prices        []string
+		postTimes     []time.Time
+		expectedPrice sdk.Dec
+		wantErr       bool
+	}{
+		{
+			"no price",
+			nil,
+			nil,
+			sdk.Dec{},
+			true,
+		},
+		{
+			"median of an odd number of prices",
+			[]string{"3.0", "1.0", "2.0"},
+			[]time.Time{start, start, start},
+			sdk.NewDec(2),
+			false,
+		},
+		{
+			"median of an even number of prices",
+			[]string{"3.0", "1.0"},
+			[]time.Time{start, start},
+			sdk.NewDec(2),
+			false,
+		},
+		{
+			"stale prices are ignored",
+			[]string{"3.0", "1.0", "2.0"},
+			[]time.Time{start.Add(-2 * time.Hour), start, start},
+			sdk.NewDecWithPrec(15, 1),
+			false,
+		},
+		{
+			"only stale prices",
+			[]string{"3.0"},
+			[]time.Time{start.Add(-2 * time.Hour)},
+			sdk.Dec{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+			params := types.DefaultParams()
+			params.Oracles = oracles
+			keeper.SetParams(ctx, params)
+
+			for i, price := range tt.prices {
+				msg := types.NewMsgPostPrice(oracles[i], "foo", sdk.MustNewDecFromStr(price))
+				require.NoError(t, keeper.PostPrice(ctx.WithBlockTime(tt.postTimes[i]), msg))
+			}
+
+			// prices from outside the whitelist are rejected
+			require.Error(t, keeper.PostPrice(ctx.WithBlockTime(start), types.NewMsgPostPrice(stranger, "foo", sdk.NewDec(100))))
+
+			price, err := keeper.GetCurrentPrice(ctx.WithBlockTime(start), "foo")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, price.Price.Equal(tt.expectedPrice), price.Price.String())
+		})
+	}
+}
+
+func TestKeeper_GetCurrentPriceIgnoresRemovedOracles(t *testing.T) {
+	oracle := sdk.AccAddress([]byte("oracle1_____________"))
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	params := types.DefaultParams()
+	params.Oracles = []sdk.AccAddress{oracle}
+	keeper.SetParams(ctx, params)
+
+	require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.NewDec(2))))
+	_, err := keeper.GetCurrentPrice(ctx, "foo")
+	require.NoError(t, err)
+
+	params.Oracles = []sdk.AccAddress{}
+	keeper.SetParams(ctx, params)
+	_, err = keeper.GetCurrentPrice(ctx, "foo")
+	require.Error(t, err)
+}
@@ -0,0 +1,214 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+const proposalStorePrefix = ":proposal:"
+
+func getProposalStoreKey(ID string) []byte {
+	return []byte(proposalStorePrefix + ID)
+}
+
+func (keeper Keeper) getProposalByID(ctx sdk.Context, id string) (types.DebtProposal, error) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	proposalKey := getProposalStoreKey(id)
+	if !store.Has(proposalKey) {
+		return types.DebtProposal{}, sdkErr.Wrapf(types.ErrProposalNotFound, "cannot find proposal with ID %s", id)
+	}
+
+	var proposal types.DebtProposal
+	keeper.cdc.MustUnmarshalBinaryBare(store.Get(proposalKey), &proposal)
+	return proposal, nil
+}
+
+// ProposeDebt stores a pending debt, that expires after the given number of blocks.
+// Its principal is escrowed in the module account until the proposal is accepted or discarded
+func (keeper Keeper) ProposeDebt(ctx sdk.Context, debt types.Debt, expiry int64) error {
+	if keeper.isUsedID(ctx, debt.ID) {
+		return sdkErr.Wrapf(types.ErrDuplicateID, "cannot propose a debt with an already used ID %s", debt.ID)
+	}
+
+	if err := keeper.applyParams(ctx, &debt); err != nil {
+		return err
+	}
+
+	minExpiry := keeper.GetParams(ctx).MinProposalExpiry
+	if expiry == 0 {
+		expiry = types.DefaultProposalExpiry
+		if expiry < minExpiry {
+			expiry = minExpiry
+		}
+	}
+	if expiry < minExpiry {
+		return sdkErr.Wrapf(types.ErrExpiryTooShort, "proposals must stay pending for at least %d blocks", minExpiry)
+	}
+
+	principal := debt.Amount.Coins()
+	if err := keeper.supplyKeeper.SendCoinsFromAccountToModule(ctx, debt.Creditor, types.ModuleName, principal); err != nil {
+		return err
+	}
+
+	if err := keeper.CreateProposal(ctx, types.NewDebtProposal(debt, ctx.BlockHeight()+expiry)); err != nil {
+		return err
+	}
+
+	keeper.emitDebtEvent(ctx, types.ActionPropose, debt, debt.Amount.String())
+	return nil
+}
+
+// CreateProposal stores a pending proposal, without escrowing its principal
+func (keeper Keeper) CreateProposal(ctx sdk.Context, proposal types.DebtProposal) error {
+	store := ctx.KVStore(keeper.storeKey)
+
+	if keeper.isUsedID(ctx, proposal.Debt.ID) {
+		return sdkErr.Wrapf(types.ErrDuplicateID, "cannot propose a debt with an already used ID %s", proposal.Debt.ID)
+	}
+
+	store.Set(getProposalStoreKey(proposal.Debt.ID), keeper.cdc.MustMarshalBinaryBare(&proposal))
+	return nil
+}
+
+// AcceptDebt turns a pending proposal into an active debt, locking its collateral
+func (keeper Keeper) AcceptDebt(ctx sdk.Context, msg types.MsgAcceptDebt) error {
+	proposal, err := keeper.getProposalByID(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Debtor.Equals(proposal.Debt.Debtor) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the proposal with ID %s is not addressed to you", msg.ID)
+	}
+
+	if proposal.IsExpired(ctx.BlockHeight()) {
+		return sdkErr.Wrapf(types.ErrProposalExpired, "the proposal with ID %s has expired", msg.ID)
+	}
+
+	if err := keeper.lockCollateral(ctx, proposal.Debt); err != nil {
+		return err
+	}
+
+	keeper.deleteProposal(ctx, msg.ID)
+
+	principal := proposal.Debt.Amount.Coins()
+	if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, msg.Debtor, principal); err != nil {
+		return err
+	}
+
+	if err := keeper.activateDebt(ctx, proposal.Debt); err != nil {
+		return err
+	}
+
+	keeper.emitDebtEvent(ctx, types.ActionAccept, proposal.Debt, proposal.Debt.Amount.String())
+	return nil
+}
+
+// RejectDebt lets the debtor discard a pending proposal
+func (keeper Keeper) RejectDebt(ctx sdk.Context, msg types.MsgRejectDebt) error {
+	proposal, err := keeper.getProposalByID(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Debtor.Equals(proposal.Debt.Debtor) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the proposal with ID %s is not addressed to you", msg.ID)
+	}
+
+	return keeper.discardProposal(ctx, proposal, types.ActionReject)
+}
+
+// WithdrawProposal lets the creditor discard a pending proposal
+func (keeper Keeper) WithdrawProposal(ctx sdk.Context, msg types.MsgWithdrawProposal) error {
+	proposal, err := keeper.getProposalByID(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Creditor.Equals(proposal.Debt.Creditor) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the proposal with ID %s is not yours", msg.ID)
+	}
+
+	return keeper.discardProposal(ctx, proposal, types.ActionWithdraw)
+}
+
+// RemoveExpiredProposals discards all proposals that cannot be accepted anymore
+func (keeper Keeper) RemoveExpiredProposals(ctx sdk.Context) {
+	expired := keeper.getProposals(ctx, func(proposal types.DebtProposal) bool {
+		return proposal.IsExpired(ctx.BlockHeight())
+	})
+
+	for _, proposal := range expired {
+		if err := keeper.discardProposal(ctx, proposal, types.ActionExpire); err != nil {
+			// the escrow always holds the principal of the pending proposals
+			panic(err)
+		}
+	}
+}
+
+// discardProposal removes a pending proposal and gives its escrowed principal back to the creditor
+func (keeper Keeper) discardProposal(ctx sdk.Context, proposal types.DebtProposal, action string) error {
+	keeper.deleteProposal(ctx, proposal.Debt.ID)
+
+	principal := proposal.Debt.Amount.Coins()
+	if err := keeper.supplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, proposal.Debt.Creditor, principal); err != nil {
+		return err
+	}
+
+	keeper.emitDebtEvent(ctx, action, proposal.Debt, proposal.Debt.Amount.String())
+	return nil
+}
+
+func (keeper Keeper) deleteProposal(ctx sdk.Context, id string) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(getProposalStoreKey(id))
+}
+
+func (keeper Keeper) proposalsIterator(ctx sdk.Context) sdk.Iterator {
+	store := ctx.KVStore(keeper.storeKey)
+	return sdk.KVStorePrefixIterator(store, []byte(proposalStorePrefix))
+}
+
+// a functional type used to filter proposals
+type proposalDiscrimination func(proposal types.DebtProposal) bool
+
+func (keeper Keeper) getProposals(ctx sdk.Context, logic proposalDiscrimination) []types.DebtProposal {
+	ri := keeper.proposalsIterator(ctx)
+	defer ri.Close()
+
+	receivedResult := []types.DebtProposal{}
+	for ; ri.Valid(); ri.Next() {
+		var proposal types.DebtProposal
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &proposal)
+
+		// we only accept proposals that satisfy the filter
+		if logic(proposal) {
+			receivedResult = append(receivedResult, proposal)
+		}
+	}
+
+	return receivedResult
+}
+
+func (keeper Keeper) GetAllProposals(ctx sdk.Context) []types.DebtProposal {
+	// we use a filter that accepts everything
+	return keeper.getProposals(ctx, func(_ types.DebtProposal) bool {
+		return true
+	})
+}
+
+func (keeper Keeper) GetDebtorProposals(ctx sdk.Context, address sdk.AccAddress) []types.DebtProposal {
+	// we use a filter that projects on the debtor
+	return keeper.getProposals(ctx, func(proposal types.DebtProposal) bool {
+		return proposal.Debt.Debtor.Equals(address)
+	})
+}
+
+func (keeper Keeper) GetCreditorProposals(ctx sdk.Context, address sdk.AccAddress) []types.DebtProposal {
+	// we use a filter that projects on the creditor
+	return keeper.getProposals(ctx, func(proposal types.DebtProposal) bool {
+		return proposal.Debt.Creditor.Equals(address)
+	})
+}
@@ -0,0 +1,159 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_ProposeDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(10)
+
+	// the creditor cannot propose a debt it cannot fund
+	require.Error(t, keeper.ProposeDebt(ctx, debt, 5))
+	require.Empty(t, keeper.GetAllProposals(ctx))
+
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(30000)))))
+	require.NoError(t, keeper.ProposeDebt(ctx, debt, 5))
+
+	// the principal is escrowed in the module account
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10000)))))
+	require.True(t, keeper.GetModuleAccountCoins(ctx).IsEqual(debt.Amount.Coins()))
+
+	// the same ID cannot be proposed twice
+	require.Error(t, keeper.ProposeDebt(ctx, debt, 5))
+
+	// a pending proposal is not an active debt
+	require.Empty(t, keeper.GetDebtorDebts(ctx, debtor))
+	require.Equal(t, []types.DebtProposal{types.NewDebtProposal(debt, 15)}, keeper.GetDebtorProposals(ctx, debtor))
+	require.Equal(t, []types.DebtProposal{types.NewDebtProposal(debt, 15)}, keeper.GetCreditorProposals(ctx, creditor))
+	require.Empty(t, keeper.GetCreditorProposals(ctx, debtor))
+
+	// an active debt cannot be created with the ID of a pending proposal
+	require.Error(t, keeper.CreateDebt(ctx, debt))
+}
+
+func TestKeeper_AcceptDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+
+	tests := []struct {
+		name    string
+		height  int64
+		msg     types.MsgAcceptDebt
+		wantErr bool
+	}{
+		{
+			"accept existing proposal",
+			12,
+			types.NewMsgAcceptDebt(debt.ID, debtor),
+			false,
+		},
+		{
+			"accept not existing proposal",
+			12,
+			types.NewMsgAcceptDebt(debt.ID+"notExisting", debtor),
+			true,
+		},
+		{
+			"accept proposal addressed to somebody else",
+			12,
+			types.NewMsgAcceptDebt(debt.ID, creditor),
+			true,
+		},
+		{
+			"accept expired proposal",
+			16,
+			types.NewMsgAcceptDebt(debt.ID, debtor),
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+
+			require.NoError(t, bankKeeper.SetCoins(ctx, creditor, debt.Amount.Coins()))
+			require.NoError(t, keeper.ProposeDebt(ctx.WithBlockHeight(10), debt, 5))
+
+			err := keeper.AcceptDebt(ctx.WithBlockHeight(tt.height), tt.msg)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Len(t, keeper.GetAllProposals(ctx), 1)
+				require.Empty(t, keeper.GetAllDebts(ctx))
+				require.True(t, keeper.GetModuleAccountCoins(ctx).IsEqual(debt.Amount.Coins()))
+				return
+			}
+
+			require.NoError(t, err)
+			require.Empty(t, keeper.GetAllProposals(ctx))
+			// interest starts accruing from the acceptance
+			activeDebt := debt
+			activeDebt.LastAccrualHeight = tt.height
+			require.Equal(t, []types.Debt{activeDebt}, keeper.GetDebtorDebts(ctx, debtor))
+
+			// the principal has been disbursed to the debtor
+			require.True(t, authKeeper.GetAccount(ctx, debtor).GetCoins().IsEqual(debt.Amount.Coins()))
+			require.True(t, keeper.GetModuleAccountCoins(ctx).Empty())
+		})
+	}
+}
+
+func TestKeeper_RejectAndWithdrawProposal(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, debt.Amount.Coins()))
+	require.NoError(t, keeper.ProposeDebt(ctx, debt, 0))
+
+	// only the debtor can reject and only the creditor can withdraw
+	require.Error(t, keeper.RejectDebt(ctx, types.NewMsgRejectDebt(debt.ID, creditor)))
+	require.Error(t, keeper.WithdrawProposal(ctx, types.NewMsgWithdrawProposal(debt.ID, debtor)))
+
+	require.NoError(t, keeper.RejectDebt(ctx, types.NewMsgRejectDebt(debt.ID, debtor)))
+	require.Empty(t, keeper.GetAllProposals(ctx))
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(debt.Amount.Coins()))
+
+	require.NoError(t, keeper.ProposeDebt(ctx, debt, 0))
+	require.NoError(t, keeper.WithdrawProposal(ctx, types.NewMsgWithdrawProposal(debt.ID, creditor)))
+	require.Empty(t, keeper.GetAllProposals(ctx))
+	require.Empty(t, keeper.GetAllDebts(ctx))
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(debt.Amount.Coins()))
+	require.True(t, keeper.GetModuleAccountCoins(ctx).Empty())
+}
+
+func TestKeeper_RemoveExpiredProposals(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	shortLived := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+	longLived := shortLived
+	longLived.ID = "A2"
+
+	_, ctx, authKeeper, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, creditor, shortLived.Amount.Add(longLived.Amount).Coins()))
+	require.NoError(t, keeper.ProposeDebt(ctx, shortLived, 1))
+	require.NoError(t, keeper.ProposeDebt(ctx, longLived, 10))
+
+	keeper.RemoveExpiredProposals(ctx.WithBlockHeight(1))
+	require.Len(t, keeper.GetAllProposals(ctx), 2)
+
+	keeper.RemoveExpiredProposals(ctx.WithBlockHeight(2))
+	require.Equal(t, []types.DebtProposal{types.NewDebtProposal(longLived, 10)}, keeper.GetAllProposals(ctx))
+
+	// the principal of the expired proposal went back to the creditor
+	require.True(t, authKeeper.GetAccount(ctx, creditor).GetCoins().IsEqual(shortLived.Amount.Coins()))
+}
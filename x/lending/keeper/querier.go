@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"fmt"
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
@@ -12,20 +13,345 @@ import (
 func NewQuerier(keeper Keeper) sdk.Querier {
 	return func(ctx sdk.Context, path []string, req abci.RequestQuery) (res []byte, err error) {
 		switch path[0] {
+		case types.QueryDebt:
+			return queryGetDebt(ctx, path[1:], keeper)
+		case types.QueryDebtHistory:
+			return queryGetDebtHistory(ctx, path[1:], req, keeper)
+		case types.QueryDebtLedger:
+			return queryGetDebtLedger(ctx, path[1:], req, keeper)
+		case types.QueryDebtSchedule:
+			return queryGetDebtSchedule(ctx, path[1:], keeper)
 		case types.QueryAllDebts:
-			return queryGetAllDebts(ctx, path[1:], keeper)
+			return queryGetAllDebts(ctx, path[1:], req, keeper)
 		case types.QueryDebtorDebts:
-			return queryGetDebtorDebts(ctx, path[1:], keeper)
+			return queryGetDebtorDebts(ctx, path[1:], req, keeper)
 		case types.QueryCreditorDebts:
-			return queryGetCreditorDebts(ctx, path[1:], keeper)
+			return queryGetCreditorDebts(ctx, path[1:], req, keeper)
+		case types.QueryAllProposals:
+			return queryGetAllProposals(ctx, path[1:], keeper)
+		case types.QueryDebtorProposals:
+			return queryGetDebtorProposals(ctx, path[1:], keeper)
+		case types.QueryCreditorProposals:
+			return queryGetCreditorProposals(ctx, path[1:], keeper)
+		case types.QueryTransfers:
+			return queryGetTransfers(ctx, keeper)
+		case types.QueryCreditLine:
+			return queryGetCreditLine(ctx, path[1:], keeper)
+		case types.QueryAllCreditLines:
+			return queryGetAllCreditLines(ctx, keeper)
+		case types.QueryBorrowerCreditLines:
+			return queryGetBorrowerCreditLines(ctx, path[1:], keeper)
+		case types.QueryLenderCreditLines:
+			return queryGetLenderCreditLines(ctx, path[1:], keeper)
+		case types.QueryPositions:
+			return queryGetPositions(ctx, path[1:], keeper)
+		case types.QueryPool:
+			return queryGetPool(ctx, path[1:], keeper)
+		case types.QueryAllPools:
+			return queryGetAllPools(ctx, keeper)
+		case types.QueryPoolDeposits:
+			return queryGetPoolDeposits(ctx, path[1:], keeper)
+		case types.QueryPoolRates:
+			return queryGetPoolRates(ctx, path[1:], keeper)
+		case types.QueryPrice:
+			return queryGetPrice(ctx, path[1:], keeper)
+		case types.QueryParams:
+			return queryGetParams(ctx, keeper)
 		default:
 			return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, fmt.Sprintf("Unknown %s query endpoint", types.ModuleName))
 		}
 	}
 }
 
-func queryGetAllDebts(ctx sdk.Context, _ []string, keeper Keeper) ([]byte, error) {
-	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetAllDebts(ctx))
+// queryDebtsParams yields the query params in the request, or the default ones if it has none
+func (keeper Keeper) queryDebtsParams(req abci.RequestQuery) (types.QueryDebtsParams, error) {
+	params := types.NewQueryDebtsParams(1, types.DefaultQueryLimit)
+	if len(req.Data) > 0 {
+		if err := keeper.cdc.UnmarshalJSON(req.Data, &params); err != nil {
+			return params, sdkErr.Wrap(sdkErr.ErrJSONUnmarshal, err.Error())
+		}
+	}
+
+	return params, params.Validate()
+}
+
+// paginate yields the bounds of the page of the query params among the given
+// number of results, or false if the page is out of bounds
+func paginate(results int, params types.QueryDebtsParams) (int, int, bool) {
+	page := params.Page
+	if page == 0 {
+		page = 1
+	}
+
+	start, end := client.Paginate(results, page, params.Limit, types.DefaultQueryLimit)
+	return start, end, start >= 0 && end >= 0
+}
+
+// selectDebts applies to the debts of the given address, or to all debts if the
+// address is empty, the filters and the pagination of the query params in the request
+func (keeper Keeper) selectDebts(debts []types.Debt, address sdk.AccAddress, req abci.RequestQuery) ([]types.Debt, error) {
+	params, err := keeper.queryDebtsParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []types.Debt{}
+	for _, debt := range debts {
+		if params.Matches(debt, address) {
+			filtered = append(filtered, debt)
+		}
+	}
+
+	start, end, ok := paginate(len(filtered), params)
+	if !ok {
+		return []types.Debt{}, nil
+	}
+
+	return filtered[start:end], nil
+}
+
+// selectArchivedDebts is like selectDebts for the archived debts
+func (keeper Keeper) selectArchivedDebts(archived []types.ArchivedDebt, address sdk.AccAddress,
+	req abci.RequestQuery) ([]types.ArchivedDebt, error) {
+	params, err := keeper.queryDebtsParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := []types.ArchivedDebt{}
+	for _, debt := range archived {
+		if params.MatchesArchived(debt, address) {
+			filtered = append(filtered, debt)
+		}
+	}
+
+	start, end, ok := paginate(len(filtered), params)
+	if !ok {
+		return []types.ArchivedDebt{}, nil
+	}
+
+	return filtered[start:end], nil
+}
+
+func queryGetDebt(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing ID")
+	}
+
+	debt, err := keeper.GetDebt(ctx, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, debt)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+// queryGetDebtHistory serves the archived debts, or only those
+// of an address if the path includes one
+func queryGetDebtHistory(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	var address sdk.AccAddress
+	if len(path) > 0 {
+		var err error
+		if address, err = sdk.AccAddressFromBech32(path[0]); err != nil {
+			return nil, sdkErr.Wrap(sdkErr.ErrInvalidAddress, path[0])
+		}
+	}
+
+	archived := keeper.GetAllArchivedDebts(ctx)
+	if !address.Empty() {
+		parties := []types.ArchivedDebt{}
+		for _, debt := range archived {
+			if debt.Debt.Debtor.Equals(address) || debt.Debt.Creditor.Equals(address) {
+				parties = append(parties, debt)
+			}
+		}
+		archived = parties
+	}
+
+	selected, err := keeper.selectArchivedDebts(archived, address, req)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, selected)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+// queryGetDebtLedger serves a page of the ledger of an active or archived debt.
+// Only the pagination of the query params applies to ledger entries
+func queryGetDebtLedger(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing ID")
+	}
+
+	id := path[0]
+	if _, err := keeper.GetDebt(ctx, id); err != nil {
+		if _, err := keeper.GetArchivedDebt(ctx, id); err != nil {
+			return nil, sdkErr.Wrapf(types.ErrDebtNotFound, "cannot find debt with ID %s", id)
+		}
+	}
+
+	params, err := keeper.queryDebtsParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := keeper.GetLedger(ctx, id)
+	start, end, ok := paginate(len(entries), params)
+	if ok {
+		entries = entries[start:end]
+	} else {
+		entries = []types.LedgerEntry{}
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, entries)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+// queryGetDebtSchedule serves the installments of an active or archived debt,
+// with what has been paid and what remains of each of them
+func queryGetDebtSchedule(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing ID")
+	}
+
+	debt, err := keeper.GetDebt(ctx, path[0])
+	if err != nil {
+		archived, err := keeper.GetArchivedDebt(ctx, path[0])
+		if err != nil {
+			return nil, sdkErr.Wrapf(types.ErrDebtNotFound, "cannot find debt with ID %s", path[0])
+		}
+		debt = archived.Debt
+	}
+
+	schedule := debt.Schedule
+	if schedule == nil {
+		schedule = types.Schedule{}
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, schedule)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetAllDebts(ctx sdk.Context, _ []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	debts, err := keeper.selectDebts(keeper.GetAllDebts(ctx), nil, req)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, debts)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetDebtorDebts(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	addr := path[0]
+	address, _ := sdk.AccAddressFromBech32(addr)
+
+	debts, err := keeper.selectDebts(keeper.GetDebtorDebts(ctx, address), address, req)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, debts)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetCreditorDebts(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, error) {
+	addr := path[0]
+	address, _ := sdk.AccAddressFromBech32(addr)
+
+	debts, err := keeper.selectDebts(keeper.GetCreditorDebts(ctx, address), address, req)
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, debts)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetAllProposals(ctx sdk.Context, _ []string, keeper Keeper) ([]byte, error) {
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetAllProposals(ctx))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetDebtorProposals(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	addr := path[0]
+	address, _ := sdk.AccAddressFromBech32(addr)
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetDebtorProposals(ctx, address))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetCreditorProposals(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	addr := path[0]
+	address, _ := sdk.AccAddressFromBech32(addr)
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetCreditorProposals(ctx, address))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+// queryGetTransfers serves the transfers waiting for the consent of the debtor
+func queryGetTransfers(ctx sdk.Context, keeper Keeper) ([]byte, error) {
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetAllTransfers(ctx))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetCreditLine(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing ID")
+	}
+
+	line, err := keeper.GetCreditLine(ctx, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, line)
 	if err2 != nil {
 		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
 	}
@@ -33,11 +359,32 @@ func queryGetAllDebts(ctx sdk.Context, _ []string, keeper Keeper) ([]byte, error
 	return bz, nil
 }
 
-func queryGetDebtorDebts(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+func queryGetAllCreditLines(ctx sdk.Context, keeper Keeper) ([]byte, error) {
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetAllCreditLines(ctx))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetBorrowerCreditLines(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	addr := path[0]
+	address, _ := sdk.AccAddressFromBech32(addr)
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetBorrowerCreditLines(ctx, address))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetLenderCreditLines(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
 	addr := path[0]
 	address, _ := sdk.AccAddressFromBech32(addr)
 
-	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetDebtorDebts(ctx, address))
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetLenderCreditLines(ctx, address))
 	if err2 != nil {
 		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
 	}
@@ -45,14 +392,105 @@ func queryGetDebtorDebts(ctx sdk.Context, path []string, keeper Keeper) ([]byte,
 	return bz, nil
 }
 
-func queryGetCreditorDebts(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+// queryGetPositions serves the debts and the credit lines of an address, on both of their sides
+func queryGetPositions(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
 	addr := path[0]
 	address, _ := sdk.AccAddressFromBech32(addr)
 
-	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetCreditorDebts(ctx, address))
+	positions := types.NewPositions(address,
+		keeper.GetDebtorDebts(ctx, address),
+		keeper.GetCreditorDebts(ctx, address),
+		keeper.GetBorrowerCreditLines(ctx, address),
+		keeper.GetLenderCreditLines(ctx, address))
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, positions)
 	if err2 != nil {
 		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
 	}
 
 	return bz, nil
-}
\ No newline at end of file
+}
+
+func queryGetPool(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing denom")
+	}
+
+	state, err := keeper.GetPoolState(ctx, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, state)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetPoolRates(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing denom")
+	}
+
+	rates, err := keeper.GetPoolRates(ctx, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, rates)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetAllPools(ctx sdk.Context, keeper Keeper) ([]byte, error) {
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetAllPoolStates(ctx))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetPoolDeposits(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	addr := path[0]
+	address, _ := sdk.AccAddressFromBech32(addr)
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetPoolDeposits(ctx, address))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetPrice(ctx sdk.Context, path []string, keeper Keeper) ([]byte, error) {
+	if len(path) == 0 {
+		return nil, sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Missing denom")
+	}
+
+	price, err := keeper.GetCurrentPrice(ctx, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, price)
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
+
+func queryGetParams(ctx sdk.Context, keeper Keeper) ([]byte, error) {
+	bz, err2 := codec.MarshalJSONIndent(keeper.cdc, keeper.GetParams(ctx))
+	if err2 != nil {
+		return nil, sdkErr.Wrap(sdkErr.ErrUnknownRequest, "Could not marshal result to JSON")
+	}
+
+	return bz, nil
+}
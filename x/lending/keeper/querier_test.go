@@ -1,9 +1,13 @@
 package keeper
 
 import (
+	"errors"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/spoto/lending/x/lending/types"
 	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
 	"testing"
 )
 
@@ -25,29 +29,14 @@ func Test_queryGetAllDebts(t *testing.T) {
 		{
 			"one debt in store",
 			[]types.Debt{
-				{
-					ID:       ID,
-					Debtor:   debtor,
-					Amount:   amount,
-					Creditor: creditor,
-				},
+				types.NewDebt(ID, debtor, amount, creditor),
 			},
 		},
 		{
 			"debts in store",
 			[]types.Debt{
-				{
-					ID:       ID,
-					Debtor:   debtor,
-					Amount:   amount,
-					Creditor: creditor,
-				},
-				{
-					ID:       ID + "A",
-					Debtor:   creditor,
-					Amount:   amount,
-					Creditor: debtor,
-				},
+				types.NewDebt(ID, debtor, amount, creditor),
+				types.NewDebt(ID+"A", creditor, amount, debtor),
 			},
 		},
 	}
@@ -59,7 +48,7 @@ func Test_queryGetAllDebts(t *testing.T) {
 				require.NoError(t, keeper.CreateDebt(ctx, debt))
 			}
 
-			result, err := queryGetAllDebts(ctx, nil, keeper)
+			result, err := queryGetAllDebts(ctx, nil, abci.RequestQuery{}, keeper)
 
 			require.NoError(t, err)
 
@@ -72,4 +61,303 @@ func Test_queryGetAllDebts(t *testing.T) {
 			require.Equal(t, tt.debts, d)
 		})
 	}
-}
\ No newline at end of file
+}
+func Test_queryDebtsWithParams(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	active := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+	defaulted := types.NewDebt("A2", debtor, sdk.NewCoin("foo", sdk.NewInt(100)), creditor)
+	defaulted.Status = types.StatusDefaulted
+	inBar := types.NewMultiCoinDebt("A3", debtor, sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(500)), sdk.NewCoin("baz", sdk.NewInt(50000))), other)
+
+	tests := []struct {
+		name    string
+		params  func(params *types.QueryDebtsParams)
+		want    []types.Debt
+		wantErr bool
+	}{
+		{"no params", nil, []types.Debt{active, defaulted, inBar}, false},
+		{"active debts", func(p *types.QueryDebtsParams) { p.Status = types.StatusActive }, []types.Debt{active, inBar}, false},
+		{"defaulted debts", func(p *types.QueryDebtsParams) { p.Status = types.StatusDefaulted }, []types.Debt{defaulted}, false},
+		{"repaid debts", func(p *types.QueryDebtsParams) { p.Status = types.StatusRepaid }, nil, false},
+		{"unknown status", func(p *types.QueryDebtsParams) { p.Status = "lost" }, nil, true},
+		{"debts in a denom", func(p *types.QueryDebtsParams) { p.Denom = "bar" }, []types.Debt{inBar}, false},
+		{"debts in one of many denoms", func(p *types.QueryDebtsParams) { p.Denom = "baz" }, []types.Debt{inBar}, false},
+		{"debts in an amount range", func(p *types.QueryDebtsParams) {
+			p.MinAmount = sdk.NewInt(100)
+			p.MaxAmount = sdk.NewInt(500)
+		}, []types.Debt{defaulted, inBar}, false},
+		{"debts in an amount range of a denom", func(p *types.QueryDebtsParams) {
+			p.Denom = "baz"
+			p.MinAmount = sdk.NewInt(100)
+			p.MaxAmount = sdk.NewInt(500)
+		}, nil, false},
+		{"inconsistent amount range", func(p *types.QueryDebtsParams) {
+			p.MinAmount = sdk.NewInt(500)
+			p.MaxAmount = sdk.NewInt(100)
+		}, nil, true},
+		{"debts with a counterparty", func(p *types.QueryDebtsParams) { p.Counterparty = other }, []types.Debt{inBar}, false},
+		{"first page", func(p *types.QueryDebtsParams) { p.Limit = 2 }, []types.Debt{active, defaulted}, false},
+		{"second page", func(p *types.QueryDebtsParams) { p.Page, p.Limit = 2, 2 }, []types.Debt{inBar}, false},
+		{"page out of bounds", func(p *types.QueryDebtsParams) { p.Page, p.Limit = 3, 2 }, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, _, keeper := SetupTestInput()
+
+			require.NoError(t, keeper.CreateDebt(ctx, active))
+			require.NoError(t, keeper.CreateDebt(ctx, defaulted))
+			require.NoError(t, keeper.CreateDebt(ctx, inBar))
+
+			var req abci.RequestQuery
+			if tt.params != nil {
+				params := types.NewQueryDebtsParams(1, 0)
+				tt.params(&params)
+				req.Data = cdc.MustMarshalJSON(params)
+			}
+
+			result, err := queryGetAllDebts(ctx, nil, req, keeper)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var d []types.Debt
+			cdc.MustUnmarshalJSON(result, &d)
+			require.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func Test_queryDebtorDebtsWithCounterparty(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other := sdk.AccAddress([]byte("other_______________"))
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(100))
+	fromCreditor := types.NewDebt("A1", debtor, amount, creditor)
+	fromOther := types.NewDebt("A2", debtor, amount, other)
+	toOther := types.NewDebt("A3", other, amount, debtor)
+
+	cdc, ctx, _, _, keeper := SetupTestInput()
+	require.NoError(t, keeper.CreateDebt(ctx, fromCreditor))
+	require.NoError(t, keeper.CreateDebt(ctx, fromOther))
+	require.NoError(t, keeper.CreateDebt(ctx, toOther))
+
+	params := types.NewQueryDebtsParams(1, 0)
+	params.Counterparty = other
+
+	// the counterparty of the debts of a debtor is their creditor
+	result, err := queryGetDebtorDebts(ctx, []string{debtor.String()}, abci.RequestQuery{Data: cdc.MustMarshalJSON(params)}, keeper)
+	require.NoError(t, err)
+
+	var d []types.Debt
+	cdc.MustUnmarshalJSON(result, &d)
+	require.Equal(t, []types.Debt{fromOther}, d)
+}
+
+func Test_queryGetDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+
+	tests := []struct {
+		name    string
+		path    []string
+		want    *types.Debt
+		wantErr error
+	}{
+		{"existing debt", []string{"A1"}, &debt, nil},
+		{"unknown debt", []string{"A2"}, nil, types.ErrDebtNotFound},
+		{"missing ID", nil, nil, sdkErr.ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, _, keeper := SetupTestInput()
+			require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+			result, err := NewQuerier(keeper)(ctx, append([]string{types.QueryDebt}, tt.path...), abci.RequestQuery{})
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var d types.Debt
+			cdc.MustUnmarshalJSON(result, &d)
+			require.Equal(t, *tt.want, d)
+		})
+	}
+}
+
+func Test_queryCreditLines(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	tests := []struct {
+		name    string
+		path    []string
+		want    int
+		wantErr error
+	}{
+		{"existing credit line", []string{types.QueryCreditLine, "L1"}, 1, nil},
+		{"unknown credit line", []string{types.QueryCreditLine, "L2"}, 0, types.ErrCreditLineNotFound},
+		{"missing ID", []string{types.QueryCreditLine}, 0, sdkErr.ErrInvalidRequest},
+		{"all credit lines", []string{types.QueryAllCreditLines}, 1, nil},
+		{"credit lines of the borrower", []string{types.QueryBorrowerCreditLines, borrower.String()}, 1, nil},
+		{"credit lines of the lender", []string{types.QueryLenderCreditLines, lender.String()}, 1, nil},
+		{"credit lines where the borrower lends", []string{types.QueryLenderCreditLines, borrower.String()}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, _, keeper := SetupTestInput()
+			line := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+			require.NoError(t, keeper.OpenCreditLine(ctx, line))
+
+			result, err := NewQuerier(keeper)(ctx, tt.path, abci.RequestQuery{})
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tt.path[0] == types.QueryCreditLine {
+				var l types.CreditLine
+				cdc.MustUnmarshalJSON(result, &l)
+				require.Equal(t, "L1", l.ID)
+				require.Equal(t, sdk.NewCoin("foo", sdk.NewInt(1000)), l.Available())
+				return
+			}
+
+			var lines []types.CreditLine
+			cdc.MustUnmarshalJSON(result, &lines)
+			require.Len(t, lines, tt.want)
+		})
+	}
+}
+
+func Test_queryPositions(t *testing.T) {
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	tests := []struct {
+		name       string
+		address    sdk.AccAddress
+		asDebtor   []string
+		asCreditor []string
+		asBorrower []string
+		asLender   []string
+	}{
+		{"positions of the borrower", borrower, []string{"A1"}, []string{"A2"}, []string{"L1"}, []string{}},
+		{"positions of the lender", lender, []string{"A2"}, []string{"A1"}, []string{}, []string{"L1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, _, keeper := SetupTestInput()
+			require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A1", borrower, sdk.NewCoin("foo", sdk.NewInt(100)), lender)))
+			require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A2", lender, sdk.NewCoin("foo", sdk.NewInt(200)), borrower)))
+			line := types.NewCreditLine("L1", lender, borrower, sdk.NewCoin("foo", sdk.NewInt(1000)))
+			require.NoError(t, keeper.OpenCreditLine(ctx, line))
+
+			path := []string{types.QueryPositions, tt.address.String()}
+			result, err := NewQuerier(keeper)(ctx, path, abci.RequestQuery{})
+			require.NoError(t, err)
+
+			var positions types.Positions
+			cdc.MustUnmarshalJSON(result, &positions)
+			require.Equal(t, tt.address, positions.Address)
+
+			debtIDs := func(debts []types.Debt) []string {
+				ids := []string{}
+				for _, debt := range debts {
+					ids = append(ids, debt.ID)
+				}
+				return ids
+			}
+			lineIDs := func(lines []types.CreditLine) []string {
+				ids := []string{}
+				for _, line := range lines {
+					ids = append(ids, line.ID)
+				}
+				return ids
+			}
+			require.Equal(t, tt.asDebtor, debtIDs(positions.AsDebtor))
+			require.Equal(t, tt.asCreditor, debtIDs(positions.AsCreditor))
+			require.Equal(t, tt.asBorrower, lineIDs(positions.AsBorrower))
+			require.Equal(t, tt.asLender, lineIDs(positions.AsLender))
+		})
+	}
+}
+
+func Test_queryPools(t *testing.T) {
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	other, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	tests := []struct {
+		name    string
+		path    []string
+		want    int
+		wantErr error
+	}{
+		{"existing pool", []string{types.QueryPool, "foo"}, 1, nil},
+		{"unknown pool", []string{types.QueryPool, "bar"}, 0, types.ErrPoolNotFound},
+		{"missing denom", []string{types.QueryPool}, 0, sdkErr.ErrInvalidRequest},
+		{"all pools", []string{types.QueryAllPools}, 1, nil},
+		{"rates of the pool", []string{types.QueryPoolRates, "foo"}, 1, nil},
+		{"rates of an unknown pool", []string{types.QueryPoolRates, "bar"}, 0, types.ErrPoolNotFound},
+		{"deposits of the lender", []string{types.QueryPoolDeposits, lender.String()}, 1, nil},
+		{"deposits of who did not deposit", []string{types.QueryPoolDeposits, other.String()}, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, bankKeeper, keeper := SetupTestInput()
+			params := types.DefaultParams()
+			params.PoolCreators = []sdk.AccAddress{creator}
+			keeper.SetParams(ctx, params)
+			require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+			require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", types.NoInterest())))
+			require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+			result, err := NewQuerier(keeper)(ctx, tt.path, abci.RequestQuery{})
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			switch tt.path[0] {
+			case types.QueryPool:
+				var s types.PoolState
+				cdc.MustUnmarshalJSON(result, &s)
+				require.Equal(t, sdk.NewInt(1000), s.Liquidity)
+				require.Equal(t, types.PoolAddress("foo"), s.Address)
+			case types.QueryPoolRates:
+				var r types.PoolRates
+				cdc.MustUnmarshalJSON(result, &r)
+				require.Equal(t, "foo", r.Denom)
+				require.Equal(t, sdk.ZeroDec(), r.Utilization)
+			case types.QueryAllPools:
+				var states []types.PoolState
+				cdc.MustUnmarshalJSON(result, &states)
+				require.Len(t, states, tt.want)
+			default:
+				var deposits []types.PoolDeposit
+				cdc.MustUnmarshalJSON(result, &deposits)
+				require.Len(t, deposits, tt.want)
+			}
+		})
+	}
+}
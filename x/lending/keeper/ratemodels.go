@@ -0,0 +1,71 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// GetPoolRates yields the current rates of the pool of the given denom
+func (keeper Keeper) GetPoolRates(ctx sdk.Context, denom string) (types.PoolRates, error) {
+	state, err := keeper.GetPoolState(ctx, denom)
+	if err != nil {
+		return types.PoolRates{}, err
+	}
+
+	return poolRates(keeper.GetParams(ctx), state), nil
+}
+
+// poolRates yields the rates of a pool: those of its rate model at its utilization,
+// capped at the max annual yield of interest, or else those of its fixed terms
+func poolRates(params types.Params, state types.PoolState) types.PoolRates {
+	pool := state.Pool
+
+	model, ok := params.RateModel(pool.Denom)
+	if !ok {
+		return types.NewPoolRates(pool.Denom, "", pool.Interest, state.Utilization, params.BlocksPerYear)
+	}
+
+	terms := model.Terms(pool.Interest, state.Utilization, params.BlocksPerYear).
+		WithMaxAnnualYield(params.MaxInterestRate, params.BlocksPerYear)
+
+	return types.NewPoolRates(pool.Denom, model.Kind, terms, state.Utilization, params.BlocksPerYear)
+}
+
+// UpdatePoolRates moves the rate of the pools with a rate model, and of the loans
+// drawn from them, to the rate of their current utilization
+func (keeper Keeper) UpdatePoolRates(ctx sdk.Context) {
+	params := keeper.GetParams(ctx)
+
+	for _, pool := range keeper.GetAllPools(ctx) {
+		if _, ok := params.RateModel(pool.Denom); !ok {
+			continue
+		}
+
+		rates := poolRates(params, keeper.poolState(ctx, pool))
+		if rates.Interest.Equal(pool.Interest) {
+			continue
+		}
+
+		pool.Interest = rates.Interest
+		keeper.SetPool(ctx, pool)
+		keeper.repricePoolLoans(ctx, pool)
+
+		keeper.emitPoolEvent(ctx, types.ActionUpdateRate, pool, "",
+			sdk.NewAttribute(types.AttributeKeyRate, pool.Interest.Rate.String()))
+	}
+}
+
+// repricePoolLoans sets the terms of the pool to the loans drawn from it
+func (keeper Keeper) repricePoolLoans(ctx sdk.Context, pool types.Pool) {
+	for _, debt := range keeper.getPoolLoans(ctx, pool) {
+		// loans that accrued nothing so far start accruing now, rather than since they were drawn
+		if debt.Interest.IsZero() {
+			debt.LastAccrualHeight, debt.LastAccrualTime = ctx.BlockHeight(), ctx.BlockTime()
+		}
+
+		debt.Interest = pool.Interest
+		if err := keeper.updateDebt(ctx, debt); err != nil {
+			panic(err)
+		}
+	}
+}
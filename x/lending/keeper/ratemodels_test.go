@@ -0,0 +1,160 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_GetPoolRates(t *testing.T) {
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	oracle := sdk.AccAddress([]byte("oracle______________"))
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	// interest accrues once a year, so that the rate per period is the annual rate
+	yearly := types.NewInterestTerms(sdk.NewDecWithPrec(5, 2), types.InterestSimple, types.SecondsPerYear, types.PeriodSeconds)
+	linear := types.NewLinearRateModel("foo", sdk.NewDecWithPrec(2, 2), sdk.NewDecWithPrec(1, 1))
+	jump := types.NewJumpRateModel("foo", sdk.NewDecWithPrec(2, 2), sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(8, 1), sdk.OneDec())
+	steep := types.NewJumpRateModel("foo", sdk.NewDecWithPrec(2, 2), sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(8, 1), sdk.NewDec(10))
+
+	tests := []struct {
+		name       string
+		models     []types.RateModel
+		borrowed   int64
+		wantBorrow string
+		wantSupply string
+	}{
+		{"fixed rate", []types.RateModel{}, 500, "0.05", "0.025"},
+		{"linear model, unused pool", []types.RateModel{linear}, 0, "0.02", "0"},
+		{"linear model", []types.RateModel{linear}, 900, "0.11", "0.099"},
+		{"jump rate model below the kink", []types.RateModel{jump}, 500, "0.07", "0.035"},
+		{"jump rate model beyond the kink", []types.RateModel{jump}, 900, "0.2", "0.18"},
+		{"rate model beyond the max annual yield", []types.RateModel{steep}, 900, "1", "0.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, bankKeeper, keeper := SetupTestInput()
+			params := types.DefaultParams()
+			params.Oracles = []sdk.AccAddress{oracle}
+			params.PoolCreators = []sdk.AccAddress{creator}
+			params.RateModels = tt.models
+			keeper.SetParams(ctx, params)
+			require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.OneDec())))
+			require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "bar", sdk.OneDec())))
+
+			require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", yearly)))
+			require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+			require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+			if tt.borrowed > 0 {
+				collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(2*tt.borrowed)))
+				require.NoError(t, bankKeeper.SetCoins(ctx, borrower, collateral))
+				amount := sdk.NewCoin("foo", sdk.NewInt(tt.borrowed))
+				require.NoError(t, keeper.BorrowFromPool(ctx, types.NewMsgBorrowFromPool("P1", borrower, amount, collateral)))
+			}
+
+			rates, err := keeper.GetPoolRates(ctx, "foo")
+			require.NoError(t, err)
+			require.Equal(t, sdk.NewDec(tt.borrowed).QuoInt64(1000), rates.Utilization)
+			require.Equal(t, sdk.MustNewDecFromStr(tt.wantBorrow), rates.BorrowAPY)
+			require.Equal(t, sdk.MustNewDecFromStr(tt.wantSupply), rates.SupplyAPY)
+
+			// the calculator yields the same rates without any state, but without caps
+			if len(tt.models) > 0 && rates.BorrowAPY.LT(params.MaxInterestRate) {
+				require.Equal(t, rates, types.CalculateRates(tt.models[0], yearly, rates.Utilization, params.BlocksPerYear))
+			}
+		})
+	}
+}
+
+func TestKeeper_UpdatePoolRates(t *testing.T) {
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	oracle := sdk.AccAddress([]byte("oracle______________"))
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(1)
+	params := types.DefaultParams()
+	params.Oracles = []sdk.AccAddress{oracle}
+	params.PoolCreators = []sdk.AccAddress{creator}
+	params.RateModels = []types.RateModel{types.NewLinearRateModel("foo", sdk.ZeroDec(), sdk.OneDec())}
+	params.BlocksPerYear = 100
+	keeper.SetParams(ctx, params)
+	require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.OneDec())))
+	require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "bar", sdk.OneDec())))
+
+	// pools with a rate model and without terms compound at each block
+	require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", types.NoInterest())))
+	pool, err := keeper.GetPool(ctx, "foo")
+	require.NoError(t, err)
+	require.True(t, pool.Interest.Equal(types.ModelTerms()))
+
+	require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+	require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(1000)))))
+	collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(900)))
+	require.NoError(t, bankKeeper.SetCoins(ctx, borrower, collateral))
+	require.NoError(t, keeper.BorrowFromPool(ctx, types.NewMsgBorrowFromPool("P1", borrower, sdk.NewCoin("foo", sdk.NewInt(600)), collateral)))
+
+	// at 60% utilization the annual rate is 60%, that is, 0.6% at each of the 100 blocks of a year
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	keeper.UpdatePoolRates(ctx)
+	require.Len(t, ctx.EventManager().Events(), 1)
+
+	pool, err = keeper.GetPool(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewDecWithPrec(6, 3), pool.Interest.Rate)
+
+	debt, err := keeper.GetDebt(ctx, "P1")
+	require.NoError(t, err)
+	require.True(t, debt.Interest.Equal(pool.Interest))
+
+	// the rate moves only when the utilization does
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	keeper.UpdatePoolRates(ctx)
+	require.Empty(t, ctx.EventManager().Events())
+
+	// the loan accrues since it was repriced, not since it was drawn
+	keeper.AccrueInterest(ctx.WithBlockHeight(2))
+	debt, err = keeper.GetDebt(ctx, "P1")
+	require.NoError(t, err)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(3))), debt.AccruedInterest)
+}
+
+func TestKeeper_PoolLoansAccrueAtDefaultParams(t *testing.T) {
+	lender, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	borrower, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	oracle := sdk.AccAddress([]byte("oracle______________"))
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockHeight(1)
+	params := types.DefaultParams()
+	params.Oracles = []sdk.AccAddress{oracle}
+	params.PoolCreators = []sdk.AccAddress{creator}
+	params.RateModels = []types.RateModel{types.NewLinearRateModel("foo", sdk.NewDecWithPrec(1, 1), sdk.ZeroDec())}
+	keeper.SetParams(ctx, params)
+	require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "foo", sdk.OneDec())))
+	require.NoError(t, keeper.PostPrice(ctx, types.NewMsgPostPrice(oracle, "bar", sdk.OneDec())))
+
+	require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "foo", types.NoInterest())))
+	require.NoError(t, bankKeeper.SetCoins(ctx, lender, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(10000000)))))
+	require.NoError(t, keeper.DepositToPool(ctx, types.NewMsgDepositToPool(lender, sdk.NewCoin("foo", sdk.NewInt(10000000)))))
+	collateral := sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(7500000)))
+	require.NoError(t, bankKeeper.SetCoins(ctx, borrower, collateral))
+	require.NoError(t, keeper.BorrowFromPool(ctx, types.NewMsgBorrowFromPool("P1", borrower, sdk.NewCoin("foo", sdk.NewInt(5000000)), collateral)))
+
+	// at 10% a year, each of the 6311520 blocks of a year accrues 0.079 coins on 5000000
+	for height := int64(1); height <= 1001; height++ {
+		blockCtx := ctx.WithBlockHeight(height)
+		keeper.AccrueInterest(blockCtx)
+		keeper.UpdatePoolRates(blockCtx)
+	}
+
+	debt, err := keeper.GetDebt(ctx, "P1")
+	require.NoError(t, err)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(79))), debt.AccruedInterest)
+}
@@ -0,0 +1,330 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+func TestKeeper_GenerateSchedule(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	hourly := types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 3600, types.PeriodSeconds)
+
+	tests := []struct {
+		name       string
+		interest   types.InterestTerms
+		repayment  types.ScheduleTerms
+		amounts    []int64
+		principals []int64
+	}{
+		{
+			"no schedule",
+			types.NoInterest(),
+			types.ScheduleTerms{},
+			nil,
+			nil,
+		},
+		{
+			"equal installments without interest",
+			types.NoInterest(),
+			types.NewScheduleTerms(types.ScheduleEqual, 3, time.Hour),
+			[]int64{333, 333, 334},
+			[]int64{333, 333, 334},
+		},
+		{
+			"equal installments with interest",
+			hourly,
+			types.NewScheduleTerms(types.ScheduleEqual, 3, time.Hour),
+			[]int64{340, 340, 339},
+			[]int64{330, 334, 336},
+		},
+		{
+			"interest-only installments and a balloon",
+			hourly,
+			types.NewScheduleTerms(types.ScheduleBalloon, 3, time.Hour),
+			[]int64{10, 10, 1010},
+			[]int64{0, 0, 1000},
+		},
+		{
+			"custom installments",
+			hourly,
+			types.NewCustomScheduleTerms(
+				types.NewInstallmentDue(start.Add(time.Hour), sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(100)))),
+				types.NewInstallmentDue(start.Add(2*time.Hour), sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(200)))),
+				types.NewInstallmentDue(start.Add(3*time.Hour), sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(750)))),
+			),
+			[]int64{100, 200, 750},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+
+			debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+			debt.Interest = tt.interest
+			debt.Repayment = tt.repayment
+			require.NoError(t, debt.Validate())
+			require.NoError(t, keeper.activateDebt(ctx.WithBlockTime(start), debt))
+
+			newDebt, err := keeper.GetDebt(ctx, debt.ID)
+			require.NoError(t, err)
+			require.Len(t, newDebt.Schedule, len(tt.amounts))
+
+			for i, installment := range newDebt.Schedule {
+				require.True(t, start.Add(time.Duration(i+1)*time.Hour).Equal(installment.DueTime))
+				require.True(t, installment.Amount.AmountOf("foo").Equal(sdk.NewInt(tt.amounts[i])))
+				require.True(t, installment.Remaining.AmountOf("foo").Equal(sdk.NewInt(tt.amounts[i])))
+				require.True(t, installment.Paid.IsZero())
+				require.Equal(t, types.InstallmentPending, installment.Status)
+				if tt.principals != nil {
+					require.True(t, installment.Principal.AmountOf("foo").Equal(sdk.NewInt(tt.principals[i])))
+				}
+			}
+		})
+	}
+}
+
+func TestDebt_ValidateRepayment(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	due := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	foo := func(amount int64) sdk.Coins { return sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(amount))) }
+
+	tooMany := make([]types.InstallmentDue, types.MaxScheduleInstallments+1)
+	for i := range tooMany {
+		tooMany[i] = types.NewInstallmentDue(due.Add(time.Duration(i)*time.Hour), foo(1))
+	}
+
+	tests := []struct {
+		name      string
+		interest  types.InterestTerms
+		repayment types.ScheduleTerms
+		wantErr   bool
+	}{
+		{"equal installments", types.NoInterest(), types.NewScheduleTerms(types.ScheduleEqual, 12, time.Hour), false},
+		{"unknown schedule", types.NoInterest(), types.NewScheduleTerms("weekly", 12, time.Hour), true},
+		{"no installments", types.NoInterest(), types.NewScheduleTerms(types.ScheduleEqual, 0, time.Hour), true},
+		{"no interval", types.NoInterest(), types.NewScheduleTerms(types.ScheduleBalloon, 12, 0), true},
+		{"installments beyond the limit", types.NoInterest(),
+			types.NewScheduleTerms(types.ScheduleEqual, types.MaxScheduleInstallments+1, time.Hour), true},
+		{"interest by blocks",
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 10, types.PeriodBlocks),
+			types.NewScheduleTerms(types.ScheduleEqual, 12, time.Hour), true},
+		{"interval not a multiple of the interest period",
+			types.NewInterestTerms(sdk.NewDecWithPrec(1, 2), types.InterestSimple, 7, types.PeriodSeconds),
+			types.NewScheduleTerms(types.ScheduleEqual, 12, time.Hour), true},
+		{"custom installments",
+			types.NoInterest(),
+			types.NewCustomScheduleTerms(types.NewInstallmentDue(due, foo(400)), types.NewInstallmentDue(due.Add(time.Hour), foo(600))), false},
+		{"custom installments short of the amount",
+			types.NoInterest(),
+			types.NewCustomScheduleTerms(types.NewInstallmentDue(due, foo(400)), types.NewInstallmentDue(due.Add(time.Hour), foo(500))), true},
+		{"custom installments out of order",
+			types.NoInterest(),
+			types.NewCustomScheduleTerms(types.NewInstallmentDue(due.Add(time.Hour), foo(400)), types.NewInstallmentDue(due, foo(600))), true},
+		{"custom installments in another denom",
+			types.NoInterest(),
+			types.NewCustomScheduleTerms(types.NewInstallmentDue(due, sdk.NewCoins(sdk.NewCoin("bar", sdk.NewInt(1000))))), true},
+		{"custom installments beyond the limit", types.NoInterest(), types.NewCustomScheduleTerms(tooMany...), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+			debt.Interest = tt.interest
+			debt.Repayment = tt.repayment
+
+			err := debt.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestKeeper_PayDebtAllocatesInstallments(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	ctx = ctx.WithBlockTime(start)
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Repayment = types.NewScheduleTerms(types.ScheduleEqual, 4, time.Hour)
+	require.NoError(t, keeper.activateDebt(ctx, debt))
+
+	// the payment settles the first installment and part of the second one
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(300)), debtor)))
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+
+	expected := []struct {
+		paid      int64
+		remaining int64
+		status    types.InstallmentStatus
+	}{
+		{250, 0, types.InstallmentPaid},
+		{50, 200, types.InstallmentPending},
+		{0, 250, types.InstallmentPending},
+		{0, 250, types.InstallmentPending},
+	}
+	for i, installment := range newDebt.Schedule {
+		require.True(t, installment.Paid.AmountOf("foo").Equal(sdk.NewInt(expected[i].paid)))
+		require.True(t, installment.Remaining.AmountOf("foo").Equal(sdk.NewInt(expected[i].remaining)))
+		require.Equal(t, expected[i].status, installment.Status)
+	}
+
+	// the archived debt keeps its schedule, fully paid
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebtInFull("A1", debtor)))
+
+	archived, err := keeper.GetArchivedDebt(ctx, "A1")
+	require.NoError(t, err)
+	for _, installment := range archived.Debt.Schedule {
+		require.True(t, installment.Remaining.IsZero())
+		require.Equal(t, types.InstallmentPaid, installment.Status)
+	}
+}
+
+func TestKeeper_UpdateInstallmentStatuses(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(sdk.NewCoin("foo", sdk.NewInt(1000)))))
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Repayment = types.NewScheduleTerms(types.ScheduleEqual, 4, time.Hour)
+	require.NoError(t, keeper.activateDebt(ctx.WithBlockTime(start), debt))
+
+	// nothing is late on the due time itself
+	ctx = ctx.WithBlockTime(start.Add(time.Hour)).WithEventManager(sdk.NewEventManager())
+	keeper.UpdateInstallmentStatuses(ctx)
+	require.Empty(t, debtActions(ctx))
+
+	ctx = ctx.WithBlockTime(start.Add(2*time.Hour + time.Minute)).WithEventManager(sdk.NewEventManager())
+	keeper.UpdateInstallmentStatuses(ctx)
+	require.Equal(t, []string{types.ActionInstallmentLate, types.ActionInstallmentLate}, debtActions(ctx))
+	require.Equal(t, "2", debtAttributes(ctx)[types.AttributeKeyInstallment])
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.InstallmentLate, newDebt.Schedule[0].Status)
+	require.Equal(t, types.InstallmentLate, newDebt.Schedule[1].Status)
+	require.Equal(t, types.InstallmentPending, newDebt.Schedule[2].Status)
+
+	// late installments are reported once, and are paid like any other
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	keeper.UpdateInstallmentStatuses(ctx)
+	require.Empty(t, debtActions(ctx))
+
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt("A1", sdk.NewCoin("foo", sdk.NewInt(250)), debtor)))
+	newDebt, err = keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.InstallmentPaid, newDebt.Schedule[0].Status)
+	require.Equal(t, types.InstallmentLate, newDebt.Schedule[1].Status)
+}
+
+func TestKeeper_ForgivenessReducesInstallments(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ctx, _, _, keeper := SetupTestInput()
+	ctx = ctx.WithBlockTime(start)
+
+	debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+	debt.Repayment = types.NewScheduleTerms(types.ScheduleEqual, 4, time.Hour)
+	debt.LateFees = types.NewLateFeeTerms(nil, sdk.NewDecWithPrec(1, 1), sdk.ZeroDec())
+	require.NoError(t, keeper.activateDebt(ctx, debt))
+
+	// the forgiven amount comes off the last installments first
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", sdk.NewCoin("foo", sdk.NewInt(600)), creditor)))
+
+	newDebt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+
+	expected := []struct {
+		forgiven  int64
+		remaining int64
+		status    types.InstallmentStatus
+	}{
+		{0, 250, types.InstallmentPending},
+		{100, 150, types.InstallmentPending},
+		{250, 0, types.InstallmentForgiven},
+		{250, 0, types.InstallmentForgiven},
+	}
+	for i, installment := range newDebt.Schedule {
+		require.True(t, installment.Forgiven.AmountOf("foo").Equal(sdk.NewInt(expected[i].forgiven)))
+		require.True(t, installment.Remaining.AmountOf("foo").Equal(sdk.NewInt(expected[i].remaining)))
+		require.Equal(t, expected[i].status, installment.Status)
+	}
+
+	// once all due times have passed, only what is still owed is late and charged fees
+	ctx = ctx.WithBlockTime(start.Add(4*time.Hour + time.Minute)).WithEventManager(sdk.NewEventManager())
+	keeper.UpdateInstallmentStatuses(ctx)
+	require.Equal(t, []string{types.ActionInstallmentLate, types.ActionLateFee, types.ActionInstallmentLate, types.ActionLateFee},
+		debtActions(ctx))
+
+	newDebt, err = keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, types.InstallmentLate, newDebt.Schedule[1].Status)
+	require.Equal(t, types.InstallmentForgiven, newDebt.Schedule[2].Status)
+	require.Equal(t, types.NewDebtCoins(sdk.NewCoin("foo", sdk.NewInt(40))), newDebt.AccruedFees)
+}
+
+func Test_queryGetDebtSchedule(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	tests := []struct {
+		name    string
+		id      string
+		rows    int
+		wantErr error
+	}{
+		{"debt with a schedule", "A1", 4, nil},
+		{"lump-sum debt", "A2", 0, nil},
+		{"unknown debt", "A3", 0, types.ErrDebtNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdc, ctx, _, _, keeper := SetupTestInput()
+
+			scheduled := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)
+			scheduled.Repayment = types.NewScheduleTerms(types.ScheduleEqual, 4, time.Hour)
+			require.NoError(t, keeper.activateDebt(ctx, scheduled))
+			require.NoError(t, keeper.activateDebt(ctx, types.NewDebt("A2", debtor, sdk.NewCoin("foo", sdk.NewInt(1000)), creditor)))
+
+			result, err := NewQuerier(keeper)(ctx, []string{types.QueryDebtSchedule, tt.id}, abci.RequestQuery{})
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var schedule types.Schedule
+			cdc.MustUnmarshalJSON(result, &schedule)
+			require.Len(t, schedule, tt.rows)
+		})
+	}
+}
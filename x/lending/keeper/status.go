@@ -0,0 +1,84 @@
+package keeper
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// UpdateDebtStatuses moves to overdue or defaulted the open debts whose
+// maturity or grace period has passed, emitting an event for each transition.
+// Debts that miss their maturity are charged their late fee on what they owe.
+// Only the debts due in the status index are loaded
+func (keeper Keeper) UpdateDebtStatuses(ctx sdk.Context) {
+	debts := keeper.getDueDebts(ctx, keeper.getDueTimeIDs(ctx, statusTimeIndexPrefix, ctx.BlockTime()))
+
+	for _, debt := range debts {
+		if debt.StatusAt(ctx.BlockTime()) == debt.Status {
+			continue
+		}
+
+		oldStatus := debt.Status
+		debt.Status = debt.StatusAt(ctx.BlockTime())
+
+		var fee types.DebtCoins
+		if oldStatus == types.StatusActive {
+			fee = keeper.chargeLateFee(ctx, &debt, debt.Owed())
+		}
+
+		if err := keeper.updateDebt(ctx, debt); err != nil {
+			panic(err)
+		}
+
+		// a debt might jump from active to defaulted in the same block
+		if oldStatus == types.StatusActive {
+			keeper.emitDebtEvent(ctx, types.ActionOverdue, debt, "")
+		}
+		if debt.Status == types.StatusDefaulted {
+			keeper.emitDebtEvent(ctx, types.ActionDefault, debt, "")
+		}
+		if !fee.IsZero() {
+			keeper.emitDebtEvent(ctx, types.ActionLateFee, debt, fee.String())
+		}
+	}
+}
+
+// UpdateInstallmentStatuses marks as late the pending installments of the open debts
+// whose due time has passed, emitting an event for each installment, numbered from 1.
+// Each late installment is charged the late fee of its debt on what remains of it.
+// Only the debts due in the installment index are loaded
+func (keeper Keeper) UpdateInstallmentStatuses(ctx sdk.Context) {
+	debts := keeper.getDueDebts(ctx, keeper.getDueTimeIDs(ctx, installmentTimeIndexPrefix, ctx.BlockTime()))
+
+	for _, debt := range debts {
+		late := debt.Schedule.LateAt(ctx.BlockTime())
+		if debt.Status.IsClosed() || len(late) == 0 {
+			continue
+		}
+
+		schedule := make(types.Schedule, len(debt.Schedule))
+		copy(schedule, debt.Schedule)
+		for _, i := range late {
+			schedule[i].Status = types.InstallmentLate
+		}
+		debt.Schedule = schedule
+
+		fees := make([]types.DebtCoins, len(late))
+		for j, i := range late {
+			fees[j] = keeper.chargeLateFee(ctx, &debt, debt.Schedule[i].Remaining)
+		}
+
+		if err := keeper.updateDebt(ctx, debt); err != nil {
+			panic(err)
+		}
+
+		for j, i := range late {
+			installment := sdk.NewAttribute(types.AttributeKeyInstallment, strconv.Itoa(i+1))
+			keeper.emitDebtEvent(ctx, types.ActionInstallmentLate, debt, debt.Schedule[i].Remaining.String(), installment)
+			if !fees[j].IsZero() {
+				keeper.emitDebtEvent(ctx, types.ActionLateFee, debt, fees[j].String(), installment)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_UpdateDebtStatuses(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	maturity := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		status          types.DebtStatus
+		maturity        time.Time
+		blockTime       time.Time
+		expectedStatus  types.DebtStatus
+		expectedActions []string
+	}{
+		{
+			"debt without maturity",
+			types.StatusActive,
+			time.Time{},
+			maturity.Add(time.Hour),
+			types.StatusActive,
+			nil,
+		},
+		{
+			"debt not yet due",
+			types.StatusActive,
+			maturity,
+			maturity,
+			types.StatusActive,
+			nil,
+		},
+		{
+			"debt due within the grace period",
+			types.StatusActive,
+			maturity,
+			maturity.Add(time.Hour),
+			types.StatusOverdue,
+			[]string{types.ActionOverdue},
+		},
+		{
+			"overdue debt past the grace period",
+			types.StatusOverdue,
+			maturity,
+			maturity.Add(48 * time.Hour),
+			types.StatusDefaulted,
+			[]string{types.ActionDefault},
+		},
+		{
+			"active debt past the grace period",
+			types.StatusActive,
+			maturity,
+			maturity.Add(48 * time.Hour),
+			types.StatusDefaulted,
+			[]string{types.ActionOverdue, types.ActionDefault},
+		},
+		{
+			"repaid debt past its maturity",
+			types.StatusRepaid,
+			maturity,
+			maturity.Add(48 * time.Hour),
+			types.StatusRepaid,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+
+			debt := types.NewDebt("A1", debtor, sdk.NewCoin("foo", sdk.NewInt(20000)), creditor)
+			debt.MaturityTime = tt.maturity
+			debt.GracePeriod = 24 * time.Hour
+			debt.Status = tt.status
+			require.NoError(t, keeper.CreateDebt(ctx, debt))
+
+			ctx = ctx.WithBlockTime(tt.blockTime).WithEventManager(sdk.NewEventManager())
+			keeper.UpdateDebtStatuses(ctx)
+
+			newDebt, err := keeper.GetDebt(ctx, debt.ID)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStatus, newDebt.Status)
+
+			require.Equal(t, tt.expectedActions, debtActions(ctx))
+		})
+	}
+}
+
+func TestKeeper_ClosingStatuses(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(20000))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(amount)))
+
+	repaid := types.NewDebt("A1", debtor, amount, creditor)
+	repaid.Status = types.StatusOverdue
+	require.NoError(t, keeper.CreateDebt(ctx, repaid))
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebt(repaid.ID, amount, debtor)))
+
+	archived, err := keeper.GetArchivedDebt(ctx, repaid.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.StatusRepaid, archived.Debt.Status)
+
+	forgiven := types.NewDebt("A2", debtor, amount, creditor)
+	require.NoError(t, keeper.CreateDebt(ctx, forgiven))
+	require.Error(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt(forgiven.ID, amount.Add(amount), creditor)))
+	require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt(forgiven.ID, amount, creditor)))
+
+	archived, err = keeper.GetArchivedDebt(ctx, forgiven.ID)
+	require.NoError(t, err)
+	require.Equal(t, types.StatusForgiven, archived.Debt.Status)
+
+	// closed debts cannot be paid or changed anymore
+	require.Error(t, keeper.PayDebt(ctx, types.NewMsgPayDebt(repaid.ID, amount, debtor)))
+	require.Error(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt(forgiven.ID, amount, creditor)))
+}
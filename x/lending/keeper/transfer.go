@@ -0,0 +1,149 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/spoto/lending/x/lending/types"
+)
+
+// transfers waiting for the consent of the debtor map the ID of the debt to the transfer
+const transferStorePrefix = ":transfer:"
+
+func getTransferStoreKey(ID string) []byte {
+	return []byte(transferStorePrefix + ID)
+}
+
+// TransferDebt lets the creditor of a debt assign it to a new creditor. If the module
+// params require the consent of the debtor, the transfer waits for it and replaces
+// any transfer of the debt still waiting; otherwise it takes place immediately
+func (keeper Keeper) TransferDebt(ctx sdk.Context, msg types.MsgTransferDebt) error {
+	debt, err := keeper.GetDebt(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Creditor.Equals(debt.Creditor) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the debt with ID %s is not yours", msg.ID)
+	}
+
+	if debt.Status.IsClosed() {
+		return sdkErr.Wrapf(types.ErrDebtClosed, "the debt with ID %s is already %s", msg.ID, debt.Status)
+	}
+
+	if msg.NewCreditor.Equals(debt.Debtor) {
+		return sdkErr.Wrapf(sdkErr.ErrInvalidRequest, "the debt with ID %s cannot be transferred to its debtor", msg.ID)
+	}
+
+	if keeper.isModuleOrPoolAddress(ctx, debt, msg.NewCreditor) {
+		return sdkErr.Wrapf(sdkErr.ErrInvalidRequest, "the debt with ID %s cannot be transferred to the module or pool account %s",
+			msg.ID, msg.NewCreditor)
+	}
+
+	if !keeper.GetParams(ctx).TransferConsent {
+		return keeper.assignDebt(ctx, debt, msg.NewCreditor)
+	}
+
+	keeper.SetTransfer(ctx, types.NewDebtTransfer(debt.ID, debt.Creditor, msg.NewCreditor))
+	keeper.emitDebtEvent(ctx, types.ActionTransferRequest, debt, "",
+		sdk.NewAttribute(types.AttributeKeyNewCreditor, msg.NewCreditor.String()))
+	return nil
+}
+
+// isModuleOrPoolAddress yields true if the address belongs to a module account or to
+// the account of a pool, that would count the debt as lent, or of the pool that the
+// denoms of the debt would have. No key controls those accounts
+func (keeper Keeper) isModuleOrPoolAddress(ctx sdk.Context, debt types.Debt, address sdk.AccAddress) bool {
+	if keeper.bankKeeper.BlacklistedAddr(address) || address.Equals(keeper.supplyKeeper.GetModuleAddress(types.ModuleName)) {
+		return true
+	}
+
+	for _, coin := range debt.Amount {
+		if address.Equals(types.PoolAddress(coin.Denom)) {
+			return true
+		}
+	}
+
+	for _, pool := range keeper.GetAllPools(ctx) {
+		if address.Equals(pool.Address()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConsentTransfer lets the debtor of a debt consent to its pending transfer
+func (keeper Keeper) ConsentTransfer(ctx sdk.Context, msg types.MsgConsentTransfer) error {
+	transfer, err := keeper.GetTransfer(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	debt, err := keeper.GetDebt(ctx, msg.ID)
+	if err != nil {
+		return err
+	}
+
+	if !msg.Debtor.Equals(debt.Debtor) {
+		return sdkErr.Wrapf(types.ErrUnauthorized, "the debt with ID %s is not yours", msg.ID)
+	}
+
+	return keeper.assignDebt(ctx, debt, transfer.NewCreditor)
+}
+
+// assignDebt makes the given address the creditor of the debt, discarding its pending transfer, if any
+func (keeper Keeper) assignDebt(ctx sdk.Context, debt types.Debt, newCreditor sdk.AccAddress) error {
+	keeper.deleteTransfer(ctx, debt.ID)
+
+	previous := debt
+	debt.Creditor = newCreditor
+	if err := keeper.updateDebt(ctx, debt); err != nil {
+		return err
+	}
+
+	keeper.recordEntry(ctx, types.EntryTransfer, debt, debt.Owed())
+	keeper.emitDebtEvent(ctx, types.ActionTransfer, previous, debt.Owed().String(),
+		sdk.NewAttribute(types.AttributeKeyNewCreditor, newCreditor.String()))
+	return nil
+}
+
+// SetTransfer stores a transfer waiting for the consent of the debtor
+func (keeper Keeper) SetTransfer(ctx sdk.Context, transfer types.DebtTransfer) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Set(getTransferStoreKey(transfer.DebtID), keeper.cdc.MustMarshalBinaryBare(&transfer))
+}
+
+// GetTransfer yields the transfer of the debt with the given ID waiting for the consent of the debtor
+func (keeper Keeper) GetTransfer(ctx sdk.Context, id string) (types.DebtTransfer, error) {
+	store := ctx.KVStore(keeper.storeKey)
+
+	transferKey := getTransferStoreKey(id)
+	if !store.Has(transferKey) {
+		return types.DebtTransfer{}, sdkErr.Wrapf(types.ErrTransferNotFound, "cannot find a pending transfer of the debt with ID %s", id)
+	}
+
+	var transfer types.DebtTransfer
+	keeper.cdc.MustUnmarshalBinaryBare(store.Get(transferKey), &transfer)
+	return transfer, nil
+}
+
+// GetAllTransfers yields all transfers waiting for the consent of the debtor
+func (keeper Keeper) GetAllTransfers(ctx sdk.Context) []types.DebtTransfer {
+	store := ctx.KVStore(keeper.storeKey)
+	ri := sdk.KVStorePrefixIterator(store, []byte(transferStorePrefix))
+	defer ri.Close()
+
+	transfers := []types.DebtTransfer{}
+	for ; ri.Valid(); ri.Next() {
+		var transfer types.DebtTransfer
+		keeper.cdc.MustUnmarshalBinaryBare(ri.Value(), &transfer)
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers
+}
+
+func (keeper Keeper) deleteTransfer(ctx sdk.Context, id string) {
+	store := ctx.KVStore(keeper.storeKey)
+	store.Delete(getTransferStoreKey(id))
+}
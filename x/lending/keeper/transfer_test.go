@@ -0,0 +1,118 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/spoto/lending/x/lending/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeeper_TransferDebt(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	buyer := sdk.AccAddress([]byte("buyer_______________"))
+	creator := sdk.AccAddress([]byte("creator_____________"))
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+
+	tests := []struct {
+		name    string
+		msg     types.MsgTransferDebt
+		wantErr error
+	}{
+		{"transfer by the creditor", types.NewMsgTransferDebt("A1", creditor, buyer), nil},
+		{"transfer by another address", types.NewMsgTransferDebt("A1", buyer, creditor), types.ErrUnauthorized},
+		{"transfer to the debtor", types.NewMsgTransferDebt("A1", creditor, debtor), sdkErr.ErrInvalidRequest},
+		{"transfer of an unknown debt", types.NewMsgTransferDebt("A2", creditor, buyer), types.ErrDebtNotFound},
+		{"transfer to the module account", types.NewMsgTransferDebt("A1", creditor, supply.NewModuleAddress(types.ModuleName)), sdkErr.ErrInvalidRequest},
+		{"transfer to the pool of its denom", types.NewMsgTransferDebt("A1", creditor, types.PoolAddress("foo")), sdkErr.ErrInvalidRequest},
+		{"transfer to another pool", types.NewMsgTransferDebt("A1", creditor, types.PoolAddress("bar")), sdkErr.ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ctx, _, _, keeper := SetupTestInput()
+			params := types.DefaultParams()
+			params.PoolCreators = []sdk.AccAddress{creator}
+			keeper.SetParams(ctx, params)
+			require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A1", debtor, amount, creditor)))
+			require.NoError(t, keeper.CreatePool(ctx, types.NewMsgCreatePool(creator, "bar", types.NoInterest())))
+
+			ctx = ctx.WithEventManager(sdk.NewEventManager())
+			err := keeper.TransferDebt(ctx, tt.msg)
+
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			// the debt moves immediately to the index of the new creditor
+			require.Equal(t, []string{"A1"}, debtIDs(keeper.GetCreditorDebts(ctx, buyer)))
+			require.Empty(t, keeper.GetCreditorDebts(ctx, creditor))
+
+			require.Equal(t, []string{types.ActionTransfer}, debtActions(ctx))
+			attributes := debtAttributes(ctx)
+			require.Equal(t, creditor.String(), attributes[types.AttributeKeyCreditor])
+			require.Equal(t, buyer.String(), attributes[types.AttributeKeyNewCreditor])
+
+			ledger := keeper.GetLedger(ctx, "A1")
+			require.Len(t, ledger, 1)
+			require.Equal(t, types.EntryTransfer, ledger[0].Type)
+			require.Equal(t, buyer, ledger[0].Creditor)
+
+			// only the new creditor can act on the debt now
+			require.Error(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", amount, creditor)))
+			require.NoError(t, keeper.ChangeDebt(ctx, types.NewMsgChangeDebt("A1", amount, buyer)))
+		})
+	}
+}
+
+func TestKeeper_TransferDebtWithConsent(t *testing.T) {
+	debtor, _ := sdk.AccAddressFromBech32("cosmos1lwmppctrr6ssnrmuyzu554dzf50apkfvd53jx0")
+	creditor, _ := sdk.AccAddressFromBech32("cosmos1tupew4x3rhh0lpqha9wvzmzxjr4e37mfy3qefm")
+	buyer := sdk.AccAddress([]byte("buyer_______________"))
+
+	amount := sdk.NewCoin("foo", sdk.NewInt(1000))
+
+	_, ctx, _, bankKeeper, keeper := SetupTestInput()
+	params := types.DefaultParams()
+	params.TransferConsent = true
+	keeper.SetParams(ctx, params)
+
+	require.NoError(t, keeper.CreateDebt(ctx, types.NewDebt("A1", debtor, amount, creditor)))
+
+	// the debtor is notified of the transfer, that waits for its consent
+	ctx = ctx.WithEventManager(sdk.NewEventManager())
+	require.NoError(t, keeper.TransferDebt(ctx, types.NewMsgTransferDebt("A1", creditor, buyer)))
+	require.Equal(t, []string{types.ActionTransferRequest}, debtActions(ctx))
+	require.Equal(t, debtor.String(), debtAttributes(ctx)[types.AttributeKeyDebtor])
+	require.Equal(t, []types.DebtTransfer{types.NewDebtTransfer("A1", creditor, buyer)}, keeper.GetAllTransfers(ctx))
+
+	debt, err := keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, creditor, debt.Creditor)
+
+	// only the debtor can consent
+	err = keeper.ConsentTransfer(ctx, types.NewMsgConsentTransfer("A1", buyer))
+	require.True(t, errors.Is(err, types.ErrUnauthorized), err)
+
+	require.NoError(t, keeper.ConsentTransfer(ctx, types.NewMsgConsentTransfer("A1", debtor)))
+	debt, err = keeper.GetDebt(ctx, "A1")
+	require.NoError(t, err)
+	require.Equal(t, buyer, debt.Creditor)
+	require.Empty(t, keeper.GetAllTransfers(ctx))
+
+	err = keeper.ConsentTransfer(ctx, types.NewMsgConsentTransfer("A1", debtor))
+	require.True(t, errors.Is(err, types.ErrTransferNotFound), err)
+
+	// the pending transfer of a debt is discarded when the debt closes
+	require.NoError(t, keeper.TransferDebt(ctx, types.NewMsgTransferDebt("A1", buyer, creditor)))
+	require.NoError(t, bankKeeper.SetCoins(ctx, debtor, sdk.NewCoins(amount)))
+	require.NoError(t, keeper.PayDebt(ctx, types.NewMsgPayDebtInFull("A1", debtor)))
+	require.Empty(t, keeper.GetAllTransfers(ctx))
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgAcceptDebt{}
+
+// MsgAcceptDebt is sent by the debtor of a pending proposal in order to activate it
+type MsgAcceptDebt struct {
+	ID     string         `json:"id"`
+	Debtor sdk.AccAddress `json:"debtor"`
+}
+
+func NewMsgAcceptDebt(id string, debtor sdk.AccAddress) MsgAcceptDebt {
+	return MsgAcceptDebt{
+		ID:     id,
+		Debtor: debtor,
+	}
+}
+
+const AcceptDebtConst = "AcceptDebt"
+
+func (msg MsgAcceptDebt) Route() string { return RouterKey }
+func (msg MsgAcceptDebt) Type() string  { return AcceptDebtConst }
+func (msg MsgAcceptDebt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Debtor}
+}
+func (msg MsgAcceptDebt) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgAcceptDebt) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Debtor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Debtor.String())
+	}
+
+	return nil
+}
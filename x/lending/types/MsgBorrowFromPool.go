@@ -0,0 +1,62 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgBorrowFromPool{}
+
+// MsgBorrowFromPool draws a loan from the pool of the denom of its amount, against
+// the given collateral. The loan is a debt with the pool as creditor, that is repaid
+// through MsgPayDebt and liquidated through MsgLiquidate, as any other debt
+type MsgBorrowFromPool struct {
+	ID         string         `json:"id"` // assigned by the keeper if empty
+	Borrower   sdk.AccAddress `json:"borrower"`
+	Amount     sdk.Coin       `json:"amount"`
+	Collateral sdk.Coins      `json:"collateral"`
+}
+
+func NewMsgBorrowFromPool(id string, borrower sdk.AccAddress, amount sdk.Coin, collateral sdk.Coins) MsgBorrowFromPool {
+	return MsgBorrowFromPool{
+		ID:         id,
+		Borrower:   borrower,
+		Amount:     amount,
+		Collateral: collateral,
+	}
+}
+
+const BorrowFromPoolConst = "BorrowFromPool"
+
+func (msg MsgBorrowFromPool) Route() string { return RouterKey }
+func (msg MsgBorrowFromPool) Type() string  { return BorrowFromPoolConst }
+func (msg MsgBorrowFromPool) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Borrower}
+}
+func (msg MsgBorrowFromPool) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgBorrowFromPool) ValidateBasic() error {
+	if err := validateClientID(msg.ID); err != nil {
+		return err
+	}
+
+	if msg.Borrower.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Borrower.String())
+	}
+
+	if msg.Amount.Amount == (sdk.Int{}) || !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, msg.Amount.String())
+	}
+
+	if msg.Collateral.Empty() {
+		return sdkErr.Wrap(ErrNoCollateral, "Loans from a pool must be collateralized")
+	}
+
+	if !msg.Collateral.IsValid() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, msg.Collateral.String())
+	}
+
+	return nil
+}
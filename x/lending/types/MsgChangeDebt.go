@@ -7,16 +7,22 @@ import (
 
 var _ sdk.Msg = &MsgChangeDebt{}
 
+// MsgChangeDebt lets the creditor forgive an amount of a debt, in some or all of its denoms
 type MsgChangeDebt struct {
 	ID       string         `json:"id"`
-	Amount   sdk.Coin       `json:"amount"`
+	Amount   DebtCoins      `json:"amount"`
 	Creditor sdk.AccAddress `json:"creditor"`
 }
 
 func NewMsgChangeDebt(id string, amount sdk.Coin, creditor sdk.AccAddress) MsgChangeDebt {
+	return NewMsgChangeDebtCoins(id, sdk.Coins{amount}, creditor)
+}
+
+// NewMsgChangeDebtCoins is like NewMsgChangeDebt for an amount in many denoms
+func NewMsgChangeDebtCoins(id string, amount sdk.Coins, creditor sdk.AccAddress) MsgChangeDebt {
 	return MsgChangeDebt{
 		ID:       id,
-		Amount:   amount,
+		Amount:   NewDebtCoins(amount...),
 		Creditor: creditor,
 	}
 }
@@ -37,8 +43,12 @@ func (msg MsgChangeDebt) ValidateBasic() error {
 		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
 	}
 
-	if msg.Amount.IsNegative() {
-		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amount should be positive")
+	if len(msg.Amount) == 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, "Amount is missing")
+	}
+
+	if err := msg.Amount.Validate(); err != nil {
+		return err
 	}
 
 	if msg.Creditor.Empty() {
@@ -0,0 +1,44 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgClaimCollateral{}
+
+// MsgClaimCollateral is sent by the creditor of a defaulted debt in order to obtain its collateral
+type MsgClaimCollateral struct {
+	ID       string         `json:"id"`
+	Creditor sdk.AccAddress `json:"creditor"`
+}
+
+func NewMsgClaimCollateral(id string, creditor sdk.AccAddress) MsgClaimCollateral {
+	return MsgClaimCollateral{
+		ID:       id,
+		Creditor: creditor,
+	}
+}
+
+const ClaimCollateralConst = "ClaimCollateral"
+
+func (msg MsgClaimCollateral) Route() string { return RouterKey }
+func (msg MsgClaimCollateral) Type() string  { return ClaimCollateralConst }
+func (msg MsgClaimCollateral) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Creditor}
+}
+func (msg MsgClaimCollateral) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgClaimCollateral) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Creditor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Creditor.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgCloseCreditLine{}
+
+// MsgCloseCreditLine is sent by the lender or the borrower of a credit line
+// in order to close it, once nothing is owed on it anymore
+type MsgCloseCreditLine struct {
+	ID     string         `json:"id"`
+	Sender sdk.AccAddress `json:"sender"`
+}
+
+func NewMsgCloseCreditLine(id string, sender sdk.AccAddress) MsgCloseCreditLine {
+	return MsgCloseCreditLine{
+		ID:     id,
+		Sender: sender,
+	}
+}
+
+const CloseCreditLineConst = "CloseCreditLine"
+
+func (msg MsgCloseCreditLine) Route() string { return RouterKey }
+func (msg MsgCloseCreditLine) Type() string  { return CloseCreditLineConst }
+func (msg MsgCloseCreditLine) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Sender}
+}
+func (msg MsgCloseCreditLine) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgCloseCreditLine) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Sender.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Sender.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgConsentTransfer{}
+
+// MsgConsentTransfer is sent by the debtor of a debt in order to let
+// its pending transfer to a new creditor take place
+type MsgConsentTransfer struct {
+	ID     string         `json:"id"`
+	Debtor sdk.AccAddress `json:"debtor"`
+}
+
+func NewMsgConsentTransfer(id string, debtor sdk.AccAddress) MsgConsentTransfer {
+	return MsgConsentTransfer{
+		ID:     id,
+		Debtor: debtor,
+	}
+}
+
+const ConsentTransferConst = "ConsentTransfer"
+
+func (msg MsgConsentTransfer) Route() string { return RouterKey }
+func (msg MsgConsentTransfer) Type() string  { return ConsentTransferConst }
+func (msg MsgConsentTransfer) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Debtor}
+}
+func (msg MsgConsentTransfer) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgConsentTransfer) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Debtor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Debtor.String())
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 
 var _ sdk.Msg = &MsgCreateDebt{}
 
+// MsgCreateDebt directly activates a debt. Since the debtor must consent
+// to it, the message must be signed by both the creditor and the debtor
 type MsgCreateDebt Debt
 
 func NewMsgCreateDebt(debt Debt) MsgCreateDebt {
@@ -17,12 +19,12 @@ const CreateDebtConst = "CreateDebt"
 func (msg MsgCreateDebt) Route() string { return RouterKey }
 func (msg MsgCreateDebt) Type() string  { return CreateDebtConst }
 func (msg MsgCreateDebt) GetSigners() []sdk.AccAddress {
-	return []sdk.AccAddress{msg.Creditor}
+	return []sdk.AccAddress{msg.Creditor, msg.Debtor}
 }
 func (msg MsgCreateDebt) GetSignBytes() []byte {
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	return sdk.MustSortJSON(bz)
 }
 func (msg MsgCreateDebt) ValidateBasic() error {
-	return Debt(msg).Validate() // delegation to Debt
+	return Debt(msg).ValidateNew() // delegation to Debt
 }
@@ -0,0 +1,42 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgCreatePool{}
+
+// MsgCreatePool creates the pool of a denom, whose loans accrue interest with the given terms
+type MsgCreatePool struct {
+	Creator  sdk.AccAddress `json:"creator"`
+	Denom    string         `json:"denom"`
+	Interest InterestTerms  `json:"interest"`
+}
+
+func NewMsgCreatePool(creator sdk.AccAddress, denom string, interest InterestTerms) MsgCreatePool {
+	return MsgCreatePool{
+		Creator:  creator,
+		Denom:    denom,
+		Interest: interest,
+	}
+}
+
+const CreatePoolConst = "CreatePool"
+
+func (msg MsgCreatePool) Route() string { return RouterKey }
+func (msg MsgCreatePool) Type() string  { return CreatePoolConst }
+func (msg MsgCreatePool) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Creator}
+}
+func (msg MsgCreatePool) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgCreatePool) ValidateBasic() error {
+	if msg.Creator.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Creator.String())
+	}
+
+	return NewPool(msg.Denom, msg.Interest).ValidateNew()
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgDepositToPool{}
+
+// MsgDepositToPool deposits an amount into the pool of its denom, in exchange for share tokens
+type MsgDepositToPool struct {
+	Depositor sdk.AccAddress `json:"depositor"`
+	Amount    sdk.Coin       `json:"amount"`
+}
+
+func NewMsgDepositToPool(depositor sdk.AccAddress, amount sdk.Coin) MsgDepositToPool {
+	return MsgDepositToPool{
+		Depositor: depositor,
+		Amount:    amount,
+	}
+}
+
+const DepositToPoolConst = "DepositToPool"
+
+func (msg MsgDepositToPool) Route() string { return RouterKey }
+func (msg MsgDepositToPool) Type() string  { return DepositToPoolConst }
+func (msg MsgDepositToPool) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Depositor}
+}
+func (msg MsgDepositToPool) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgDepositToPool) ValidateBasic() error {
+	if msg.Depositor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Depositor.String())
+	}
+
+	if msg.Amount.Amount == (sdk.Int{}) || !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, msg.Amount.String())
+	}
+
+	return nil
+}
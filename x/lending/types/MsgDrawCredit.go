@@ -0,0 +1,50 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgDrawCredit{}
+
+// MsgDrawCredit lets the borrower of a credit line draw an amount from it, within its limit
+type MsgDrawCredit struct {
+	ID       string         `json:"id"`
+	Amount   sdk.Coin       `json:"amount"`
+	Borrower sdk.AccAddress `json:"borrower"`
+}
+
+func NewMsgDrawCredit(id string, amount sdk.Coin, borrower sdk.AccAddress) MsgDrawCredit {
+	return MsgDrawCredit{
+		ID:       id,
+		Amount:   amount,
+		Borrower: borrower,
+	}
+}
+
+const DrawCreditConst = "DrawCredit"
+
+func (msg MsgDrawCredit) Route() string { return RouterKey }
+func (msg MsgDrawCredit) Type() string  { return DrawCreditConst }
+func (msg MsgDrawCredit) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Borrower}
+}
+func (msg MsgDrawCredit) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgDrawCredit) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Borrower.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Borrower.String())
+	}
+
+	if msg.Amount.Amount == (sdk.Int{}) || !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, msg.Amount.String())
+	}
+
+	return nil
+}
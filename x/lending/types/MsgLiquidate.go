@@ -0,0 +1,45 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgLiquidate{}
+
+// MsgLiquidate is sent by anybody willing to pay an under-collateralized
+// debt in exchange for its collateral at a discount
+type MsgLiquidate struct {
+	ID         string         `json:"id"`
+	Liquidator sdk.AccAddress `json:"liquidator"`
+}
+
+func NewMsgLiquidate(id string, liquidator sdk.AccAddress) MsgLiquidate {
+	return MsgLiquidate{
+		ID:         id,
+		Liquidator: liquidator,
+	}
+}
+
+const LiquidateConst = "Liquidate"
+
+func (msg MsgLiquidate) Route() string { return RouterKey }
+func (msg MsgLiquidate) Type() string  { return LiquidateConst }
+func (msg MsgLiquidate) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Liquidator}
+}
+func (msg MsgLiquidate) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgLiquidate) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Liquidator.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Liquidator.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var _ sdk.Msg = &MsgOpenCreditLine{}
+
+// MsgOpenCreditLine is sent by a lender in order to grant a credit line to a borrower.
+// The borrower owes nothing until it draws from the line through MsgDrawCredit
+type MsgOpenCreditLine struct {
+	Line CreditLine `json:"line"`
+}
+
+func NewMsgOpenCreditLine(line CreditLine) MsgOpenCreditLine {
+	return MsgOpenCreditLine{
+		Line: line,
+	}
+}
+
+const OpenCreditLineConst = "OpenCreditLine"
+
+func (msg MsgOpenCreditLine) Route() string { return RouterKey }
+func (msg MsgOpenCreditLine) Type() string  { return OpenCreditLineConst }
+func (msg MsgOpenCreditLine) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Line.Lender}
+}
+func (msg MsgOpenCreditLine) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgOpenCreditLine) ValidateBasic() error {
+	return msg.Line.ValidateNew()
+}
@@ -7,20 +7,37 @@ import (
 
 var _ sdk.Msg = &MsgPayDebt{}
 
+// MsgPayDebt pays back an amount of a debt, in some or all of its denoms, never more
+// than what is owed. In full mode, it pays exactly what is owed, including accrued interest
 type MsgPayDebt struct {
 	ID     string         `json:"id"`
-	Amount sdk.Coin       `json:"amount"`
+	Amount DebtCoins      `json:"amount"`
 	Debtor sdk.AccAddress `json:"debtor"`
+	InFull bool           `json:"in_full"`
 }
 
 func NewMsgPayDebt(id string, amount sdk.Coin, debtor sdk.AccAddress) MsgPayDebt {
+	return NewMsgPayDebtCoins(id, sdk.Coins{amount}, debtor)
+}
+
+// NewMsgPayDebtCoins is like NewMsgPayDebt for a payment in many denoms
+func NewMsgPayDebtCoins(id string, amount sdk.Coins, debtor sdk.AccAddress) MsgPayDebt {
 	return MsgPayDebt{
 		ID:     id,
-		Amount: amount,
+		Amount: NewDebtCoins(amount...),
 		Debtor: debtor,
 	}
 }
 
+// NewMsgPayDebtInFull yields a message that settles the whole balance of the debt
+func NewMsgPayDebtInFull(id string, debtor sdk.AccAddress) MsgPayDebt {
+	return MsgPayDebt{
+		ID:     id,
+		Debtor: debtor,
+		InFull: true,
+	}
+}
+
 const PayDebtConst = "PayDebt"
 
 func (msg MsgPayDebt) Route() string { return RouterKey }
@@ -41,9 +58,17 @@ func (msg MsgPayDebt) ValidateBasic() error {
 		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Debtor.String())
 	}
 
-	if msg.Amount.IsNegative() {
-		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amount should be positive")
+	if msg.InFull {
+		if !msg.Amount.IsZero() {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amount can't be given when paying in full")
+		}
+
+		return nil
+	}
+
+	if len(msg.Amount) == 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, "Amount is missing")
 	}
 
-	return nil
+	return msg.Amount.Validate()
 }
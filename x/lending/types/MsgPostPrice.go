@@ -0,0 +1,50 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgPostPrice{}
+
+// MsgPostPrice is sent by a whitelisted oracle in order to report the price of a denom
+type MsgPostPrice struct {
+	Oracle sdk.AccAddress `json:"oracle"`
+	Denom  string         `json:"denom"`
+	Price  sdk.Dec        `json:"price"`
+}
+
+func NewMsgPostPrice(oracle sdk.AccAddress, denom string, price sdk.Dec) MsgPostPrice {
+	return MsgPostPrice{
+		Oracle: oracle,
+		Denom:  denom,
+		Price:  price,
+	}
+}
+
+const PostPriceConst = "PostPrice"
+
+func (msg MsgPostPrice) Route() string { return RouterKey }
+func (msg MsgPostPrice) Type() string  { return PostPriceConst }
+func (msg MsgPostPrice) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Oracle}
+}
+func (msg MsgPostPrice) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgPostPrice) ValidateBasic() error {
+	if msg.Oracle.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Oracle.String())
+	}
+
+	if err := sdk.ValidateDenom(msg.Denom); err != nil {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, err.Error())
+	}
+
+	if msg.Price.IsNil() || !msg.Price.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Price should be positive")
+	}
+
+	return nil
+}
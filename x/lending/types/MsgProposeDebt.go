@@ -0,0 +1,45 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgProposeDebt{}
+
+// MsgProposeDebt offers a debt to a debtor. The debt becomes
+// active only after the debtor accepts it through MsgAcceptDebt
+type MsgProposeDebt struct {
+	Debt   Debt  `json:"debt"`
+	Expiry int64 `json:"expiry"` // number of blocks before the proposal expires
+}
+
+func NewMsgProposeDebt(debt Debt, expiry int64) MsgProposeDebt {
+	return MsgProposeDebt{
+		Debt:   debt,
+		Expiry: expiry,
+	}
+}
+
+const ProposeDebtConst = "ProposeDebt"
+
+func (msg MsgProposeDebt) Route() string { return RouterKey }
+func (msg MsgProposeDebt) Type() string  { return ProposeDebtConst }
+func (msg MsgProposeDebt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Debt.Creditor}
+}
+func (msg MsgProposeDebt) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgProposeDebt) ValidateBasic() error {
+	if msg.Expiry < 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Expiry can't be negative")
+	}
+
+	if msg.Debt.Debtor.Equals(msg.Debt.Creditor) {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Debtor and creditor must be different")
+	}
+
+	return msg.Debt.ValidateNew()
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgRejectDebt{}
+
+// MsgRejectDebt is sent by the debtor of a pending proposal in order to discard it
+type MsgRejectDebt struct {
+	ID     string         `json:"id"`
+	Debtor sdk.AccAddress `json:"debtor"`
+}
+
+func NewMsgRejectDebt(id string, debtor sdk.AccAddress) MsgRejectDebt {
+	return MsgRejectDebt{
+		ID:     id,
+		Debtor: debtor,
+	}
+}
+
+const RejectDebtConst = "RejectDebt"
+
+func (msg MsgRejectDebt) Route() string { return RouterKey }
+func (msg MsgRejectDebt) Type() string  { return RejectDebtConst }
+func (msg MsgRejectDebt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Debtor}
+}
+func (msg MsgRejectDebt) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgRejectDebt) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Debtor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Debtor.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgRepayCredit{}
+
+// MsgRepayCredit pays back an amount of a credit line, never more than what is owed.
+// In full mode, it pays exactly what is owed, including accrued interest
+type MsgRepayCredit struct {
+	ID       string         `json:"id"`
+	Amount   sdk.Coin       `json:"amount"`
+	Borrower sdk.AccAddress `json:"borrower"`
+	InFull   bool           `json:"in_full"`
+}
+
+func NewMsgRepayCredit(id string, amount sdk.Coin, borrower sdk.AccAddress) MsgRepayCredit {
+	return MsgRepayCredit{
+		ID:       id,
+		Amount:   amount,
+		Borrower: borrower,
+	}
+}
+
+// NewMsgRepayCreditInFull yields a message that settles the whole balance of the line
+func NewMsgRepayCreditInFull(id string, borrower sdk.AccAddress) MsgRepayCredit {
+	return MsgRepayCredit{
+		ID:       id,
+		Borrower: borrower,
+		InFull:   true,
+	}
+}
+
+const RepayCreditConst = "RepayCredit"
+
+func (msg MsgRepayCredit) Route() string { return RouterKey }
+func (msg MsgRepayCredit) Type() string  { return RepayCreditConst }
+func (msg MsgRepayCredit) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Borrower}
+}
+func (msg MsgRepayCredit) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgRepayCredit) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Borrower.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Borrower.String())
+	}
+
+	if msg.InFull {
+		if msg.Amount.Denom != "" {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amount can't be given when paying in full")
+		}
+
+		return nil
+	}
+
+	if msg.Amount.Amount == (sdk.Int{}) || !msg.Amount.IsValid() || !msg.Amount.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, msg.Amount.String())
+	}
+
+	return nil
+}
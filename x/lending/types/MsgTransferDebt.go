@@ -0,0 +1,54 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgTransferDebt{}
+
+// MsgTransferDebt is sent by the creditor of a debt in order to assign it to a new creditor
+type MsgTransferDebt struct {
+	ID          string         `json:"id"`
+	Creditor    sdk.AccAddress `json:"creditor"`
+	NewCreditor sdk.AccAddress `json:"new_creditor"`
+}
+
+func NewMsgTransferDebt(id string, creditor, newCreditor sdk.AccAddress) MsgTransferDebt {
+	return MsgTransferDebt{
+		ID:          id,
+		Creditor:    creditor,
+		NewCreditor: newCreditor,
+	}
+}
+
+const TransferDebtConst = "TransferDebt"
+
+func (msg MsgTransferDebt) Route() string { return RouterKey }
+func (msg MsgTransferDebt) Type() string  { return TransferDebtConst }
+func (msg MsgTransferDebt) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Creditor}
+}
+func (msg MsgTransferDebt) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgTransferDebt) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Creditor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Creditor.String())
+	}
+
+	if msg.NewCreditor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.NewCreditor.String())
+	}
+
+	if msg.Creditor.Equals(msg.NewCreditor) {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Creditor and new creditor must be different")
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgWithdrawFromPool{}
+
+// MsgWithdrawFromPool burns share tokens of the pool of a denom, in exchange
+// for what they are worth, as long as the pool has enough liquidity
+type MsgWithdrawFromPool struct {
+	Depositor sdk.AccAddress `json:"depositor"`
+	Denom     string         `json:"denom"`
+	Shares    sdk.Int        `json:"shares"`
+}
+
+func NewMsgWithdrawFromPool(depositor sdk.AccAddress, denom string, shares sdk.Int) MsgWithdrawFromPool {
+	return MsgWithdrawFromPool{
+		Depositor: depositor,
+		Denom:     denom,
+		Shares:    shares,
+	}
+}
+
+const WithdrawFromPoolConst = "WithdrawFromPool"
+
+func (msg MsgWithdrawFromPool) Route() string { return RouterKey }
+func (msg MsgWithdrawFromPool) Type() string  { return WithdrawFromPoolConst }
+func (msg MsgWithdrawFromPool) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Depositor}
+}
+func (msg MsgWithdrawFromPool) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgWithdrawFromPool) ValidateBasic() error {
+	if msg.Depositor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Depositor.String())
+	}
+
+	if err := sdk.ValidateDenom(msg.Denom); err != nil {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, err.Error())
+	}
+
+	if msg.Shares == (sdk.Int{}) || !msg.Shares.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Shares should be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+var _ sdk.Msg = &MsgWithdrawProposal{}
+
+// MsgWithdrawProposal is sent by the creditor of a pending proposal in order to discard it
+type MsgWithdrawProposal struct {
+	ID       string         `json:"id"`
+	Creditor sdk.AccAddress `json:"creditor"`
+}
+
+func NewMsgWithdrawProposal(id string, creditor sdk.AccAddress) MsgWithdrawProposal {
+	return MsgWithdrawProposal{
+		ID:       id,
+		Creditor: creditor,
+	}
+}
+
+const WithdrawProposalConst = "WithdrawProposal"
+
+func (msg MsgWithdrawProposal) Route() string { return RouterKey }
+func (msg MsgWithdrawProposal) Type() string  { return WithdrawProposalConst }
+func (msg MsgWithdrawProposal) GetSigners() []sdk.AccAddress {
+	return []sdk.AccAddress{msg.Creditor}
+}
+func (msg MsgWithdrawProposal) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+func (msg MsgWithdrawProposal) ValidateBasic() error {
+	if msg.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if msg.Creditor.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, msg.Creditor.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ArchivedDebt is a debt that has been closed, together with
+// the block where it was closed. Its settlement tells how
+type ArchivedDebt struct {
+	Debt         Debt      `json:"debt"`
+	ClosedHeight int64     `json:"closed_height"`
+	ClosedTime   time.Time `json:"closed_time"`
+}
+
+func NewArchivedDebt(debt Debt, closedHeight int64, closedTime time.Time) ArchivedDebt {
+	return ArchivedDebt{
+		Debt:         debt,
+		ClosedHeight: closedHeight,
+		ClosedTime:   closedTime,
+	}
+}
+
+func (archived ArchivedDebt) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`%s
+Closed at height: %d
+Closed at time: %s`, archived.Debt, archived.ClosedHeight, archived.ClosedTime))
+}
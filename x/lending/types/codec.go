@@ -9,6 +9,23 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgCreateDebt{}, "lending/CreateDebt", nil)
 	cdc.RegisterConcrete(MsgPayDebt{}, "lending/PayDebt", nil)
 	cdc.RegisterConcrete(MsgChangeDebt{}, "lending/ChangeDebt", nil)
+	cdc.RegisterConcrete(MsgProposeDebt{}, "lending/ProposeDebt", nil)
+	cdc.RegisterConcrete(MsgAcceptDebt{}, "lending/AcceptDebt", nil)
+	cdc.RegisterConcrete(MsgRejectDebt{}, "lending/RejectDebt", nil)
+	cdc.RegisterConcrete(MsgWithdrawProposal{}, "lending/WithdrawProposal", nil)
+	cdc.RegisterConcrete(MsgClaimCollateral{}, "lending/ClaimCollateral", nil)
+	cdc.RegisterConcrete(MsgPostPrice{}, "lending/PostPrice", nil)
+	cdc.RegisterConcrete(MsgLiquidate{}, "lending/Liquidate", nil)
+	cdc.RegisterConcrete(MsgTransferDebt{}, "lending/TransferDebt", nil)
+	cdc.RegisterConcrete(MsgConsentTransfer{}, "lending/ConsentTransfer", nil)
+	cdc.RegisterConcrete(MsgOpenCreditLine{}, "lending/OpenCreditLine", nil)
+	cdc.RegisterConcrete(MsgDrawCredit{}, "lending/DrawCredit", nil)
+	cdc.RegisterConcrete(MsgRepayCredit{}, "lending/RepayCredit", nil)
+	cdc.RegisterConcrete(MsgCloseCreditLine{}, "lending/CloseCreditLine", nil)
+	cdc.RegisterConcrete(MsgCreatePool{}, "lending/CreatePool", nil)
+	cdc.RegisterConcrete(MsgDepositToPool{}, "lending/DepositToPool", nil)
+	cdc.RegisterConcrete(MsgWithdrawFromPool{}, "lending/WithdrawFromPool", nil)
+	cdc.RegisterConcrete(MsgBorrowFromPool{}, "lending/BorrowFromPool", nil)
 }
 
 // ModuleCdc defines the module codec
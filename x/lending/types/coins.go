@@ -0,0 +1,198 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// DebtCoins are the amounts of a debt, such as what it still owes or what a payment
+// covers, with at most one coin per denom, sorted by denom. Unlike sdk.Coins, they keep
+// zero coins, so that a debt remembers its denoms once settled. In JSON, a single coin
+// is written as an object, as clients of single-denom debts expect, and any other
+// number of coins as an array. Both forms are accepted when reading
+type DebtCoins []sdk.Coin
+
+// NewDebtCoins yields the given coins sorted by denom
+func NewDebtCoins(coins ...sdk.Coin) DebtCoins {
+	sorted := make(DebtCoins, len(coins))
+	copy(sorted, coins)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Denom < sorted[j].Denom })
+	return sorted
+}
+
+// Coins yields the positive coins, as the bank expects them
+func (coins DebtCoins) Coins() sdk.Coins {
+	positive := sdk.NewCoins()
+	for _, coin := range coins {
+		if coin.IsPositive() {
+			positive = append(positive, coin)
+		}
+	}
+
+	return positive
+}
+
+// Add yields the sum of the coins, in the denoms of both
+func (coins DebtCoins) Add(other DebtCoins) DebtCoins {
+	return withDenoms(coins.Coins().Add(other.Coins()...), coins, other)
+}
+
+// Sub yields the difference of the coins, in the denoms of both.
+// It panics if some amount becomes negative
+func (coins DebtCoins) Sub(other DebtCoins) DebtCoins {
+	return withDenoms(coins.Coins().Sub(other.Coins()), coins, other)
+}
+
+// Min yields, for each denom of the coins, the smaller of their amount and that of other
+func (coins DebtCoins) Min(other DebtCoins) DebtCoins {
+	min := make(DebtCoins, len(coins))
+	for i, coin := range coins {
+		min[i] = sdk.NewCoin(coin.Denom, sdk.MinInt(coin.Amount, other.AmountOf(coin.Denom)))
+	}
+
+	return min
+}
+
+// Zero yields zero coins in the same denoms
+func (coins DebtCoins) Zero() DebtCoins {
+	zero := make(DebtCoins, len(coins))
+	for i, coin := range coins {
+		zero[i] = sdk.NewCoin(coin.Denom, sdk.ZeroInt())
+	}
+
+	return zero
+}
+
+// AmountOf yields the amount in the given denom, zero if the coins have no such denom
+func (coins DebtCoins) AmountOf(denom string) sdk.Int {
+	for _, coin := range coins {
+		if coin.Denom == denom {
+			return coin.Amount
+		}
+	}
+
+	return sdk.ZeroInt()
+}
+
+// HasDenom yields true if the coins include a coin, maybe zero, in the given denom
+func (coins DebtCoins) HasDenom(denom string) bool {
+	for _, coin := range coins {
+		if coin.Denom == denom {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsZero yields true if no coin is positive. Coins without amount count as zero
+func (coins DebtCoins) IsZero() bool {
+	for _, coin := range coins {
+		if coin.Amount != (sdk.Int{}) && !coin.Amount.IsZero() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsAnyNegative yields true if some coin is negative
+func (coins DebtCoins) IsAnyNegative() bool {
+	for _, coin := range coins {
+		if coin.Amount != (sdk.Int{}) && coin.IsNegative() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsAllGTE yields true if, for each denom of other, the coins have at least the same amount
+func (coins DebtCoins) IsAllGTE(other DebtCoins) bool {
+	return coins.Coins().IsAllGTE(other.Coins())
+}
+
+// Validate checks that the coins have an amount that is not negative
+// and a valid denom, and that their denoms are sorted and distinct
+func (coins DebtCoins) Validate() error {
+	for i, coin := range coins {
+		if coin.Amount == (sdk.Int{}) {
+			return sdkErr.Wrap(sdkErr.ErrInvalidCoins, "Amount is missing")
+		}
+
+		if coin.IsNegative() {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amount should be positive")
+		}
+
+		if err := sdk.ValidateDenom(coin.Denom); err != nil {
+			return sdkErr.Wrap(sdkErr.ErrInvalidCoins, err.Error())
+		}
+
+		if i > 0 && coins[i-1].Denom >= coin.Denom {
+			return sdkErr.Wrapf(sdkErr.ErrInvalidCoins, "denoms of %s must be sorted and distinct", coins)
+		}
+	}
+
+	return nil
+}
+
+func (coins DebtCoins) String() string {
+	return sdk.Coins(coins).String()
+}
+
+// MarshalJSON writes a single coin as an object and any other number of coins as an array
+func (coins DebtCoins) MarshalJSON() ([]byte, error) {
+	if len(coins) == 1 {
+		return json.Marshal(coins[0])
+	}
+
+	if coins == nil {
+		return json.Marshal([]sdk.Coin{})
+	}
+
+	return json.Marshal([]sdk.Coin(coins))
+}
+
+// UnmarshalJSON reads either a single coin object or an array of coins
+func (coins *DebtCoins) UnmarshalJSON(bz []byte) error {
+	if trimmed := bytes.TrimSpace(bz); len(trimmed) > 0 && trimmed[0] == '{' {
+		var coin sdk.Coin
+		if err := json.Unmarshal(trimmed, &coin); err != nil {
+			return err
+		}
+
+		*coins = DebtCoins{coin}
+		return nil
+	}
+
+	var list []sdk.Coin
+	if err := json.Unmarshal(bz, &list); err != nil {
+		return err
+	}
+
+	// empty coins are read back as they are stored
+	if len(list) == 0 {
+		list = nil
+	}
+
+	*coins = list
+	return nil
+}
+
+// withDenoms yields the coins with a zero coin for each denom of the given coins they lack
+func withDenoms(coins sdk.Coins, denoms ...DebtCoins) DebtCoins {
+	result := NewDebtCoins(coins...)
+	for _, other := range denoms {
+		for _, coin := range other {
+			if !result.HasDenom(coin.Denom) {
+				result = append(result, sdk.NewCoin(coin.Denom, sdk.ZeroInt()))
+			}
+		}
+	}
+
+	return NewDebtCoins(result...)
+}
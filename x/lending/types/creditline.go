@@ -0,0 +1,165 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// CreditLine is revolving credit that a lender grants to a borrower: the borrower
+// draws up to Limit, repays and draws again until ExpiryTime, if any. Interest
+// accrues on what is drawn. The draws of escrowed lines come from the module
+// account, funded by the lender with the whole limit when the line is opened,
+// while the draws of the other lines come from the lender at each draw
+type CreditLine struct {
+	ID       string         `json:"ID"`
+	Lender   sdk.AccAddress `json:"lender"`
+	Borrower sdk.AccAddress `json:"borrower"`
+	Limit    sdk.Int        `json:"limit"`
+	Denom    string         `json:"denom"`
+
+	// how interest accrues on what is drawn, and the interest accrued but not paid yet
+	Interest          InterestTerms `json:"interest"`
+	AccruedInterest   sdk.Int       `json:"accrued_interest"`
+	InterestRemainder sdk.Dec       `json:"interest_remainder"` // the fraction of coin accrued but not added to the interest yet
+	LastAccrualHeight int64         `json:"last_accrual_height"`
+	LastAccrualTime   time.Time     `json:"last_accrual_time"`
+
+	// when the borrower stops being able to draw, if ever
+	ExpiryTime time.Time `json:"expiry_time"`
+	Escrowed   bool      `json:"escrowed"`
+
+	// the principal drawn and not repaid yet, never beyond the limit
+	Drawn sdk.Int `json:"drawn"`
+}
+
+// NewCreditLine yields an interest-free line without expiry, with nothing drawn yet
+func NewCreditLine(id string, lender, borrower sdk.AccAddress, limit sdk.Coin) CreditLine {
+	return CreditLine{
+		ID:                id,
+		Lender:            lender,
+		Borrower:          borrower,
+		Limit:             limit.Amount,
+		Denom:             limit.Denom,
+		Interest:          NoInterest(),
+		AccruedInterest:   sdk.ZeroInt(),
+		InterestRemainder: sdk.ZeroDec(),
+		Drawn:             sdk.ZeroInt(),
+	}
+}
+
+// HasExpiry yields true if the borrower can draw only until some time
+func (l CreditLine) HasExpiry() bool {
+	return !l.ExpiryTime.IsZero()
+}
+
+// IsExpired yields true if the borrower cannot draw anymore at the given time
+func (l CreditLine) IsExpired(blockTime time.Time) bool {
+	return l.HasExpiry() && blockTime.After(l.ExpiryTime)
+}
+
+// Available yields what the borrower can still draw
+func (l CreditLine) Available() sdk.Coin {
+	return sdk.NewCoin(l.Denom, l.Limit.Sub(l.Drawn))
+}
+
+// Owed yields what the borrower must pay back: what is drawn and the accrued interest
+func (l CreditLine) Owed() sdk.Coin {
+	return sdk.NewCoin(l.Denom, l.Drawn.Add(l.AccruedInterest))
+}
+
+// Utilization yields the share of the limit that is drawn
+func (l CreditLine) Utilization() sdk.Dec {
+	if !l.Limit.IsPositive() {
+		return sdk.ZeroDec()
+	}
+
+	return l.Drawn.ToDec().QuoInt(l.Limit)
+}
+
+func (l CreditLine) Validate() error {
+	if l.ID == "" {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
+	}
+
+	if err := l.validateTerms(); err != nil {
+		return err
+	}
+
+	if l.Drawn == (sdk.Int{}) || l.Drawn.IsNegative() || l.Drawn.GT(l.Limit) {
+		return sdkErr.Wrapf(ErrCreditLimitExceeded, "the drawn amount must be between zero and the limit %s", l.Limit)
+	}
+
+	if l.AccruedInterest == (sdk.Int{}) || l.AccruedInterest.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Accrued interest can't be negative")
+	}
+
+	if !l.InterestRemainder.IsNil() && l.InterestRemainder.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interest remainder can't be negative")
+	}
+
+	return nil
+}
+
+// ValidateNew validates a line sent in a message, whose ID can be left
+// empty in order to have one assigned by the keeper
+func (l CreditLine) ValidateNew() error {
+	if err := validateClientID(l.ID); err != nil {
+		return err
+	}
+
+	// the remainder is only carried by the keeper, from one accrual to the next
+	if !l.InterestRemainder.IsNil() && !l.InterestRemainder.IsZero() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interest remainder must be zero")
+	}
+
+	return l.validateTerms()
+}
+
+func (l CreditLine) validateTerms() error {
+	if l.Lender.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, l.Lender.String())
+	}
+
+	if l.Borrower.Empty() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, l.Borrower.String())
+	}
+
+	if l.Lender.Equals(l.Borrower) {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Lender and borrower must be different")
+	}
+
+	if err := sdk.ValidateDenom(l.Denom); err != nil {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, err.Error())
+	}
+
+	if l.Limit == (sdk.Int{}) || !l.Limit.IsPositive() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, "Limit should be positive")
+	}
+
+	return l.Interest.Validate()
+}
+
+func (l CreditLine) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`ID: %s
+                Lender: %s
+                Borrower: %s
+                Limit: %s%s
+                Drawn: %s%s
+                Interest: %s
+                Accrued interest: %s%s
+                Expiry: %s
+                Escrowed: %t`,
+		l.ID,
+		l.Lender,
+		l.Borrower,
+		l.Limit, l.Denom,
+		l.Drawn, l.Denom,
+		l.Interest,
+		l.AccruedInterest, l.Denom,
+		l.ExpiryTime,
+		l.Escrowed))
+}
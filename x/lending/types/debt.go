@@ -3,16 +3,142 @@ package types
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 type Debt struct {
-	ID     string         `json:"ID"`
-	Debtor sdk.AccAddress `json:"debtor"`
-	Amount   sdk.Coin       `json:"amount"`
+	ID       string         `json:"ID"`
+	Debtor   sdk.AccAddress `json:"debtor"`
+	Amount   DebtCoins      `json:"amount"`
 	Creditor sdk.AccAddress `json:"creditor"`
+
+	// the principal originally disbursed to the debtor, while
+	// Amount is what is still outstanding
+	Principal DebtCoins `json:"principal"`
+
+	// how interest accrues, and the interest accrued but not paid yet
+	Interest          InterestTerms `json:"interest"`
+	AccruedInterest   DebtCoins     `json:"accrued_interest"`
+	InterestRemainder sdk.DecCoins  `json:"interest_remainder"` // the fractions of coin accrued but not added to the interest yet
+	LastAccrualHeight int64         `json:"last_accrual_height"`
+	LastAccrualTime   time.Time     `json:"last_accrual_time"`
+
+	// when the debt is due, if ever, and how long after that it defaults
+	MaturityTime time.Time     `json:"maturity_time"`
+	GracePeriod  time.Duration `json:"grace_period"`
+	Status       DebtStatus    `json:"status"`
+
+	// the penalties for missing due dates, and the late fees charged but not paid yet
+	LateFees    LateFeeTerms `json:"late_fees"`
+	AccruedFees DebtCoins    `json:"accrued_fees"`
+
+	// coins of the debtor locked in the module account until the debt is closed
+	Collateral sdk.Coins `json:"collateral"`
+
+	// how much of the debt has been paid or forgiven so far
+	Settlement Settlement `json:"settlement"`
+
+	// how the debt is repaid in installments, if at all, and the
+	// installments generated from those terms when the debt is created
+	Repayment ScheduleTerms `json:"repayment"`
+	Schedule  Schedule      `json:"schedule"`
+}
+
+// NewDebt yields an interest-free debt in a single denom whose whole principal is still outstanding
+func NewDebt(id string, debtor sdk.AccAddress, amount sdk.Coin, creditor sdk.AccAddress) Debt {
+	return NewMultiCoinDebt(id, debtor, sdk.Coins{amount}, creditor)
+}
+
+// NewMultiCoinDebt is like NewDebt for a debt in as many denoms as the coins of its amount
+func NewMultiCoinDebt(id string, debtor sdk.AccAddress, amount sdk.Coins, creditor sdk.AccAddress) Debt {
+	principal := NewDebtCoins(amount...)
+
+	return Debt{
+		ID:              id,
+		Debtor:          debtor,
+		Amount:          principal,
+		Creditor:        creditor,
+		Principal:       principal,
+		Interest:        NoInterest(),
+		AccruedInterest: principal.Zero(),
+		LateFees:        NoLateFees(),
+		AccruedFees:     principal.Zero(),
+		Status:          StatusActive,
+		Settlement:      NewSettlement(principal),
+	}
+}
+
+// HasMaturity yields true if the debt must be repaid by some time
+func (d Debt) HasMaturity() bool {
+	return !d.MaturityTime.IsZero()
+}
+
+// StatusAt yields the status that an open debt should have at the given time
+func (d Debt) StatusAt(blockTime time.Time) DebtStatus {
+	if d.Status.IsClosed() || !d.HasMaturity() || !blockTime.After(d.MaturityTime) {
+		return d.Status
+	}
+
+	if blockTime.After(d.MaturityTime.Add(d.GracePeriod)) {
+		return StatusDefaulted
+	}
+
+	return StatusOverdue
+}
+
+// NextStatusTime yields the time after which the status of the debt changes by
+// itself: to overdue after its maturity and to defaulted after its grace period.
+// It yields false if the status of the debt cannot change with time anymore
+func (d Debt) NextStatusTime() (time.Time, bool) {
+	if d.Status.IsClosed() || d.Status == StatusDefaulted || !d.HasMaturity() {
+		return time.Time{}, false
+	}
+
+	if d.Status == StatusOverdue {
+		return d.MaturityTime.Add(d.GracePeriod), true
+	}
+
+	return d.MaturityTime, true
+}
+
+// IsLate yields true if the debt has missed a due date: its maturity or that of an installment
+func (d Debt) IsLate() bool {
+	if d.Status == StatusOverdue || d.Status == StatusDefaulted {
+		return true
+	}
+
+	for _, installment := range d.Schedule {
+		if installment.Status == InstallmentLate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InPenalty yields true if the interest of the debt accrues at its penalty rate
+func (d Debt) InPenalty() bool {
+	return d.LateFees.HasPenaltyRate() && d.IsLate()
+}
+
+// CurrentInterest yields the interest terms that currently apply to the debt:
+// its own, or with the penalty rate in place of their rate while in penalty
+func (d Debt) CurrentInterest() InterestTerms {
+	if !d.InPenalty() {
+		return d.Interest
+	}
+
+	terms := d.Interest
+	terms.Rate = d.LateFees.PenaltyRate
+	return terms
+}
+
+// Owed yields the total that the debtor must still pay, including accrued interest and late fees
+func (d Debt) Owed() DebtCoins {
+	return d.Amount.Add(d.AccruedInterest).Add(d.AccruedFees)
 }
 
 func (d Debt) Validate() error {
@@ -20,28 +146,96 @@ func (d Debt) Validate() error {
 		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "ID can't be empty")
 	}
 
+	return d.validateTerms()
+}
+
+// ValidateNew validates a debt sent in a message, whose ID can be left
+// empty in order to have one assigned by the keeper
+func (d Debt) ValidateNew() error {
+	if err := validateClientID(d.ID); err != nil {
+		return err
+	}
+
+	// the remainder is only carried by the keeper, from one accrual to the next
+	if !d.InterestRemainder.IsZero() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interest remainder must be zero")
+	}
+
+	// nothing can be settled before the debt is created
+	if !d.Settlement.IsZero() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Settlement must be zero")
+	}
+
+	return d.validateTerms()
+}
+
+func (d Debt) validateTerms() error {
 	if d.Debtor.Empty() {
 		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, d.Debtor.String())
 	}
 
-	if d.Amount.IsNegative() {
-		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amount should be positive")
+	if len(d.Amount) == 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, "Amount is missing")
+	}
+
+	if err := d.Amount.Validate(); err != nil {
+		return err
 	}
 
 	if d.Creditor.Empty() {
 		return sdkErr.Wrap(sdkErr.ErrInvalidAddress, (d.Creditor.String()))
 	}
 
-	return nil
+	if !d.Collateral.Empty() && !d.Collateral.IsValid() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, d.Collateral.String())
+	}
+
+	if d.GracePeriod < 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Grace period can't be negative")
+	}
+
+	if err := d.Interest.Validate(); err != nil {
+		return err
+	}
+
+	if err := d.LateFees.validateFor(d.Amount, d.Interest); err != nil {
+		return err
+	}
+
+	return d.Repayment.validateFor(d.Amount, d.Interest)
 }
 
 func (d Debt) String() string {
 	return strings.TrimSpace(fmt.Sprintf(`ID: %s
                 Debtor: %s
                 Amount: %s
-                Creditor: %s`,
+                Creditor: %s
+                Principal: %s
+                Interest: %s
+                Accrued interest: %s
+                Interest remainder: %s
+                Maturity: %s
+                Grace period: %s
+                Late fees: %s
+                Accrued fees: %s
+                Status: %s
+                Collateral: %s
+                Settlement: %s
+                Repayment: %s`,
 		d.ID,
 		d.Debtor,
 		d.Amount,
-		d.Creditor))
+		d.Creditor,
+		d.Principal,
+		d.Interest,
+		d.AccruedInterest,
+		d.InterestRemainder,
+		d.MaturityTime,
+		d.GracePeriod,
+		d.LateFees,
+		d.AccruedFees,
+		d.Status,
+		d.Collateral,
+		d.Settlement,
+		d.Repayment.Type))
 }
\ No newline at end of file
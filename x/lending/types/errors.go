@@ -4,8 +4,36 @@ import (
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
-// TODO: Fill out some custom errors for the module
-// You can see how they are constructed below:
+// errors of the lending module, reported with their code in the lending codespace.
+// Codes start from 2 since 1 is reserved by the SDK for internal errors
 var (
-	ErrInvalid = sdkerrors.Register(ModuleName, 1, "custom error message")
+	ErrDebtNotFound          = sdkerrors.Register(ModuleName, 2, "debt not found")
+	ErrProposalNotFound      = sdkerrors.Register(ModuleName, 3, "proposal not found")
+	ErrDuplicateID           = sdkerrors.Register(ModuleName, 4, "ID already used")
+	ErrUnauthorized          = sdkerrors.Register(ModuleName, 5, "not a party of the debt")
+	ErrDebtClosed            = sdkerrors.Register(ModuleName, 6, "debt already closed")
+	ErrInvalidDebtStatus     = sdkerrors.Register(ModuleName, 7, "invalid debt status")
+	ErrProposalExpired       = sdkerrors.Register(ModuleName, 8, "proposal expired")
+	ErrInvalidAmount         = sdkerrors.Register(ModuleName, 9, "invalid amount")
+	ErrDenomNotAllowed       = sdkerrors.Register(ModuleName, 10, "denom not allowed")
+	ErrAmountTooLarge        = sdkerrors.Register(ModuleName, 11, "amount too large")
+	ErrInterestRateTooHigh   = sdkerrors.Register(ModuleName, 12, "interest rate too high")
+	ErrExpiryTooShort        = sdkerrors.Register(ModuleName, 13, "expiry too short")
+	ErrNotOracle             = sdkerrors.Register(ModuleName, 14, "not a whitelisted oracle")
+	ErrPriceNotFound         = sdkerrors.Register(ModuleName, 15, "no fresh price")
+	ErrNotLiquidatable       = sdkerrors.Register(ModuleName, 16, "debt cannot be liquidated")
+	ErrNoCollateral          = sdkerrors.Register(ModuleName, 17, "no collateral")
+	ErrDenomMismatch         = sdkerrors.Register(ModuleName, 18, "denom mismatch")
+	ErrTransferNotFound      = sdkerrors.Register(ModuleName, 19, "transfer not found")
+	ErrLateFeeTooHigh        = sdkerrors.Register(ModuleName, 20, "late fee too high")
+	ErrCreditLineNotFound    = sdkerrors.Register(ModuleName, 21, "credit line not found")
+	ErrCreditLimitExceeded   = sdkerrors.Register(ModuleName, 22, "credit limit exceeded")
+	ErrCreditLineExpired     = sdkerrors.Register(ModuleName, 23, "credit line expired")
+	ErrCreditLineInUse       = sdkerrors.Register(ModuleName, 24, "credit line still owed")
+	ErrPoolNotFound          = sdkerrors.Register(ModuleName, 25, "pool not found")
+	ErrPoolExists            = sdkerrors.Register(ModuleName, 26, "pool already exists")
+	ErrInsufficientLiquidity = sdkerrors.Register(ModuleName, 27, "insufficient pool liquidity")
+	ErrUndercollateralized   = sdkerrors.Register(ModuleName, 28, "insufficient collateral")
+	ErrTooManyInstallments   = sdkerrors.Register(ModuleName, 29, "too many installments")
+	ErrNotPoolCreator        = sdkerrors.Register(ModuleName, 30, "not a whitelisted pool creator")
 )
@@ -1,15 +1,67 @@
 package types
 
-// lending module event types
+// lending module event types. Every transition of a debt or of a proposal
+// emits an event of type debt, every transition of a credit line an event
+// of type credit_line and every movement of a pool an event of type pool,
+// whose action attribute tells the transition
 const (
-	// TODO: Create your event types
-	// EventType<Action>    		= "action"
+	EventTypeDebt       = "debt"
+	EventTypeCreditLine = "credit_line"
+	EventTypePool       = "pool"
 
-	// TODO: Create keys fo your events, the values will be derivided from the msg
-	// AttributeKeyAddress  		= "address"
-
-	// TODO: Some events may not have values for that reason you want to emit that something happened.
-	// AttributeValueDoubleSign = "double_sign"
+	AttributeKeyAction      = "action"
+	AttributeKeyDebtID      = "debt_id"
+	AttributeKeyDebtor      = "debtor"
+	AttributeKeyCreditor    = "creditor"
+	AttributeKeyAmount      = "amount"
+	AttributeKeyRemaining   = "remaining"
+	AttributeKeyLiquidator  = "liquidator"
+	AttributeKeyNewCreditor = "new_creditor"
+	AttributeKeyInstallment = "installment"
+	AttributeKeyLineID      = "line_id"
+	AttributeKeyLender      = "lender"
+	AttributeKeyBorrower    = "borrower"
+	AttributeKeyDenom       = "denom"
+	AttributeKeyDepositor   = "depositor"
+	AttributeKeyShares      = "shares"
+	AttributeKeyRate        = "rate"
 
 	AttributeValueCategory = ModuleName
 )
+
+// values of the action attribute of debt events
+const (
+	ActionCreate          = "create"
+	ActionPay             = "pay"
+	ActionChange          = "change"
+	ActionPropose         = "propose"
+	ActionAccept          = "accept"
+	ActionReject          = "reject"
+	ActionWithdraw        = "withdraw"
+	ActionExpire          = "expire"
+	ActionOverdue         = "overdue"
+	ActionDefault         = "default"
+	ActionClaimCollateral = "claim_collateral"
+	ActionLiquidate       = "liquidate"
+	ActionTransferRequest = "transfer_request"
+	ActionTransfer        = "transfer"
+	ActionInstallmentLate = "installment_late"
+	ActionLateFee         = "late_fee"
+)
+
+// values of the action attribute of credit line events
+const (
+	ActionOpenLine  = "open"
+	ActionDraw      = "draw"
+	ActionRepay     = "repay"
+	ActionCloseLine = "close"
+)
+
+// values of the action attribute of pool events
+const (
+	ActionCreatePool      = "create"
+	ActionDeposit         = "deposit"
+	ActionWithdrawDeposit = "withdraw"
+	ActionBorrow          = "borrow"
+	ActionUpdateRate      = "update_rate"
+)
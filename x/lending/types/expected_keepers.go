@@ -3,6 +3,7 @@ package types
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
+	supplyexported "github.com/cosmos/cosmos-sdk/x/supply/exported"
 )
 
 // ParamSubspace defines the expected Subspace interfacace
@@ -13,12 +14,13 @@ type ParamSubspace interface {
 	SetParamSet(ctx sdk.Context, ps params.ParamSet)
 }
 
-/*
-When a module wishes to interact with another module, it is good practice to define what it will use
-as an interface so the module cannot use things that are not permitted.
-TODO: Create interfaces of what you expect the other keepers to have to be able to use this module.
-type BankKeeper interface {
-	SubtractCoins(ctx sdk.Context, addr sdk.AccAddress, amt sdk.Coins) (sdk.Coins, error)
-	SendCoins(ctx sdk.Context, fromAddr sdk.AccAddress, toAddr sdk.AccAddress, amt sdk.Coins) error
+// SupplyKeeper defines the expected supply keeper, used to move coins in and out
+// of the lending module account, and to mint and burn the share tokens of the pools
+type SupplyKeeper interface {
+	GetModuleAddress(moduleName string) sdk.AccAddress
+	SendCoinsFromAccountToModule(ctx sdk.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+	SendCoinsFromModuleToAccount(ctx sdk.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
+	MintCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) error
+	BurnCoins(ctx sdk.Context, moduleName string, amt sdk.Coins) error
+	GetSupply(ctx sdk.Context) supplyexported.SupplyI
 }
-*/
@@ -0,0 +1,105 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// LateFeeTerms are the penalties of a debt that misses a due date: its maturity or
+// the due time of one of its installments. Each missed date charges FlatFee plus Rate
+// times the amount overdue, and while the debt is late its interest accrues at
+// PenaltyRate instead of the rate of its interest terms, if a penalty rate is set
+type LateFeeTerms struct {
+	FlatFee     DebtCoins `json:"flat_fee"`
+	Rate        sdk.Dec   `json:"rate"`
+	PenaltyRate sdk.Dec   `json:"penalty_rate"`
+}
+
+func NewLateFeeTerms(flatFee sdk.Coins, rate, penaltyRate sdk.Dec) LateFeeTerms {
+	terms := LateFeeTerms{
+		Rate:        rate,
+		PenaltyRate: penaltyRate,
+	}
+	if len(flatFee) > 0 {
+		terms.FlatFee = NewDebtCoins(flatFee...)
+	}
+
+	return terms
+}
+
+// NoLateFees yields the terms of a debt that is never penalised
+func NoLateFees() LateFeeTerms {
+	return NewLateFeeTerms(nil, sdk.ZeroDec(), sdk.ZeroDec())
+}
+
+// IsZero yields true if the debt is never penalised with these terms
+func (terms LateFeeTerms) IsZero() bool {
+	return terms.FlatFee.IsZero() && !terms.HasRate() && !terms.HasPenaltyRate()
+}
+
+// HasRate yields true if missed dates are charged a share of the amount overdue
+func (terms LateFeeTerms) HasRate() bool {
+	return !terms.Rate.IsNil() && !terms.Rate.IsZero()
+}
+
+// HasPenaltyRate yields true if interest accrues at a penalty rate while the debt is late
+func (terms LateFeeTerms) HasPenaltyRate() bool {
+	return !terms.PenaltyRate.IsNil() && !terms.PenaltyRate.IsZero()
+}
+
+// FeeFor yields the fee for missing a due date with the given amount overdue.
+// In each denom, the fee is at most maxRate times the amount overdue
+func (terms LateFeeTerms) FeeFor(overdue DebtCoins, maxRate sdk.Dec) DebtCoins {
+	fee := make([]sdk.Coin, len(overdue))
+	for i, coin := range overdue {
+		amount := terms.FlatFee.AmountOf(coin.Denom)
+		if terms.HasRate() {
+			amount = amount.Add(terms.Rate.MulInt(coin.Amount).TruncateInt())
+		}
+
+		fee[i] = sdk.NewCoin(coin.Denom, sdk.MinInt(amount, maxRate.MulInt(coin.Amount).TruncateInt()))
+	}
+
+	return NewDebtCoins(fee...)
+}
+
+// validateFor checks the terms of a debt of the given amount and interest terms.
+// A penalty rate accrues with the method and period of the interest terms
+func (terms LateFeeTerms) validateFor(amount DebtCoins, interest InterestTerms) error {
+	if len(terms.FlatFee) > 0 {
+		if err := terms.FlatFee.Validate(); err != nil {
+			return err
+		}
+
+		for _, coin := range terms.FlatFee {
+			if !amount.HasDenom(coin.Denom) {
+				return sdkErr.Wrapf(ErrDenomMismatch, "the debt is in %s, not %s", amount, coin.Denom)
+			}
+		}
+	}
+
+	if !terms.Rate.IsNil() && terms.Rate.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Late fee rate can't be negative")
+	}
+
+	if !terms.HasPenaltyRate() {
+		return nil
+	}
+
+	if terms.PenaltyRate.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Penalty rate can't be negative")
+	}
+
+	penalty := interest
+	penalty.Rate = terms.PenaltyRate
+	return penalty.Validate()
+}
+
+func (terms LateFeeTerms) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Flat fee: %s
+Rate: %s
+Penalty rate: %s`, terms.FlatFee, terms.Rate, terms.PenaltyRate))
+}
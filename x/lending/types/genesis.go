@@ -1,26 +1,263 @@
 package types
 
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+)
+
 // GenesisState - all lending state that must be provided at genesis
 type GenesisState struct {
-	// TODO: Fill out what is needed by the module for genesis
+	Params    Params         `json:"params"`
+	Debts     []Debt         `json:"debts"`
+	Proposals []DebtProposal `json:"proposals"`
+	Prices    []PostedPrice  `json:"prices"`
+
+	ArchivedDebts []ArchivedDebt `json:"archived_debts"`
+	LedgerEntries []LedgerEntry  `json:"ledger_entries"`
+	Transfers     []DebtTransfer `json:"transfers"`
+
+	CreditLines []CreditLine `json:"credit_lines"`
+	Pools       []Pool       `json:"pools"`
+	PoolLoans   []PoolLoan   `json:"pool_loans"`
+
+	NextDebtSequence uint64 `json:"next_debt_sequence"`
 }
 
 // NewGenesisState creates a new GenesisState object
-func NewGenesisState( /* TODO: Fill out with what is needed for genesis state */) GenesisState {
+func NewGenesisState(params Params, debts []Debt, proposals []DebtProposal, prices []PostedPrice,
+	archivedDebts []ArchivedDebt, ledgerEntries []LedgerEntry, transfers []DebtTransfer,
+	creditLines []CreditLine, pools []Pool, poolLoans []PoolLoan, nextDebtSequence uint64) GenesisState {
 	return GenesisState{
-		// TODO: Fill out according to your genesis state
+		Params:           params,
+		Debts:            debts,
+		Proposals:        proposals,
+		Prices:           prices,
+		ArchivedDebts:    archivedDebts,
+		LedgerEntries:    ledgerEntries,
+		Transfers:        transfers,
+		CreditLines:      creditLines,
+		Pools:            pools,
+		PoolLoans:        poolLoans,
+		NextDebtSequence: nextDebtSequence,
 	}
 }
 
 // DefaultGenesisState - default GenesisState used by Cosmos Hub
 func DefaultGenesisState() GenesisState {
-	return GenesisState{
-		// TODO: Fill out according to your genesis state, these values will be initialized but empty
-	}
+	return NewGenesisState(DefaultParams(), []Debt{}, []DebtProposal{}, []PostedPrice{}, []ArchivedDebt{},
+		[]LedgerEntry{}, []DebtTransfer{}, []CreditLine{}, []Pool{}, []PoolLoan{}, DefaultNextDebtSequence)
 }
 
 // ValidateGenesis validates the lending genesis parameters
 func ValidateGenesis(data GenesisState) error {
-	// TODO: Create a sanity check to make sure the state conforms to the modules needs
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+
+	if data.NextDebtSequence < DefaultNextDebtSequence {
+		return fmt.Errorf("next debt sequence must be at least %d", DefaultNextDebtSequence)
+	}
+
+	// debts, proposals, archived debts and credit lines share the same namespace of IDs
+	usedIDs := make(map[string]bool)
+
+	for _, debt := range data.Debts {
+		if usedIDs[debt.ID] {
+			return fmt.Errorf("duplicate debt ID %s", debt.ID)
+		}
+		usedIDs[debt.ID] = true
+
+		if err := validateGenesisDebt(debt, data.NextDebtSequence); err != nil {
+			return err
+		}
+	}
+
+	for _, proposal := range data.Proposals {
+		if usedIDs[proposal.Debt.ID] {
+			return fmt.Errorf("duplicate debt ID %s", proposal.Debt.ID)
+		}
+		usedIDs[proposal.Debt.ID] = true
+
+		if err := validateGenesisDebt(proposal.Debt, data.NextDebtSequence); err != nil {
+			return err
+		}
+	}
+
+	for _, archived := range data.ArchivedDebts {
+		if usedIDs[archived.Debt.ID] {
+			return fmt.Errorf("duplicate debt ID %s", archived.Debt.ID)
+		}
+		usedIDs[archived.Debt.ID] = true
+
+		if err := validateGenesisDebt(archived.Debt, data.NextDebtSequence); err != nil {
+			return err
+		}
+
+		if !archived.Debt.Status.IsClosed() {
+			return fmt.Errorf("archived debt %s is %s, not closed", archived.Debt.ID, archived.Debt.Status)
+		}
+	}
+
+	// ledger entries belong to known debts and are numbered only once per debt
+	sequences := make(map[string]map[uint64]bool)
+	for _, entry := range data.LedgerEntries {
+		if !usedIDs[entry.DebtID] {
+			return fmt.Errorf("ledger entry %d of unknown debt %s", entry.Sequence, entry.DebtID)
+		}
+		if entry.Sequence == 0 {
+			return fmt.Errorf("ledger entry of debt %s has sequence 0", entry.DebtID)
+		}
+		if sequences[entry.DebtID] == nil {
+			sequences[entry.DebtID] = make(map[uint64]bool)
+		}
+		if sequences[entry.DebtID][entry.Sequence] {
+			return fmt.Errorf("duplicate ledger entry %d of debt %s", entry.Sequence, entry.DebtID)
+		}
+		sequences[entry.DebtID][entry.Sequence] = true
+
+		if !entry.Type.IsValid() {
+			return fmt.Errorf("ledger entry %d of debt %s has unknown type %s", entry.Sequence, entry.DebtID, entry.Type)
+		}
+	}
+
+	// pending transfers come from the current creditor of an active debt
+	activeDebts := make(map[string]Debt)
+	for _, debt := range data.Debts {
+		activeDebts[debt.ID] = debt
+	}
+	transferred := make(map[string]bool)
+	for _, transfer := range data.Transfers {
+		debt, ok := activeDebts[transfer.DebtID]
+		if !ok {
+			return fmt.Errorf("transfer of unknown debt %s", transfer.DebtID)
+		}
+		if transferred[transfer.DebtID] {
+			return fmt.Errorf("duplicate transfer of debt %s", transfer.DebtID)
+		}
+		transferred[transfer.DebtID] = true
+
+		if !transfer.Creditor.Equals(debt.Creditor) {
+			return fmt.Errorf("transfer of debt %s from %s, that is not its creditor", transfer.DebtID, transfer.Creditor)
+		}
+		if transfer.NewCreditor.Empty() || transfer.NewCreditor.Equals(debt.Debtor) || transfer.NewCreditor.Equals(debt.Creditor) {
+			return fmt.Errorf("transfer of debt %s to invalid new creditor %s", transfer.DebtID, transfer.NewCreditor)
+		}
+		if isModuleOrPoolAddress(transfer.NewCreditor, debt, data.Pools) {
+			return fmt.Errorf("transfer of debt %s to the module or pool account %s", transfer.DebtID, transfer.NewCreditor)
+		}
+	}
+
+	for _, line := range data.CreditLines {
+		if usedIDs[line.ID] {
+			return fmt.Errorf("duplicate debt ID %s", line.ID)
+		}
+		usedIDs[line.ID] = true
+
+		if err := line.Validate(); err != nil {
+			return fmt.Errorf("invalid credit line %s: %s", line.ID, err)
+		}
+
+		if sequence, ok := ParseAutoID(line.ID); ok && sequence >= data.NextDebtSequence {
+			return fmt.Errorf("credit line %s was assigned an ID beyond the next debt sequence %d", line.ID, data.NextDebtSequence)
+		}
+	}
+
+	pools := make(map[string]bool)
+	for _, pool := range data.Pools {
+		if pools[pool.Denom] {
+			return fmt.Errorf("duplicate pool for %s", pool.Denom)
+		}
+		pools[pool.Denom] = true
+
+		if err := pool.Validate(); err != nil {
+			return fmt.Errorf("invalid pool for %s: %s", pool.Denom, err)
+		}
+	}
+
+	// pool loans are active debts owed to the pool that lent them
+	loans := make(map[string]bool)
+	for _, loan := range data.PoolLoans {
+		if !pools[loan.Denom] {
+			return fmt.Errorf("loan %s of unknown pool for %s", loan.DebtID, loan.Denom)
+		}
+		debt, ok := activeDebts[loan.DebtID]
+		if !ok {
+			return fmt.Errorf("pool loan of unknown debt %s", loan.DebtID)
+		}
+		if loans[loan.DebtID] {
+			return fmt.Errorf("duplicate pool loan %s", loan.DebtID)
+		}
+		loans[loan.DebtID] = true
+
+		if !debt.Creditor.Equals(PoolAddress(loan.Denom)) || !debt.Amount.HasDenom(loan.Denom) {
+			return fmt.Errorf("debt %s is not a loan of the pool for %s", loan.DebtID, loan.Denom)
+		}
+	}
+
+	for _, price := range data.Prices {
+		if price.Oracle.Empty() {
+			return fmt.Errorf("price of %s posted by an empty oracle address", price.Denom)
+		}
+		if price.Price.IsNil() || !price.Price.IsPositive() {
+			return fmt.Errorf("price of %s posted by %s is not positive", price.Denom, price.Oracle)
+		}
+	}
+
 	return nil
 }
+
+func validateGenesisDebt(debt Debt, nextDebtSequence uint64) error {
+	if err := debt.Validate(); err != nil {
+		return fmt.Errorf("invalid debt %s: %s", debt.ID, err)
+	}
+
+	if sequence, ok := ParseAutoID(debt.ID); ok && sequence >= nextDebtSequence {
+		return fmt.Errorf("debt %s was assigned an ID beyond the next debt sequence %d", debt.ID, nextDebtSequence)
+	}
+
+	if debt.Debtor.Equals(debt.Creditor) {
+		return fmt.Errorf("debt %s is reflexive", debt.ID)
+	}
+
+	if debt.AccruedInterest.IsAnyNegative() {
+		return fmt.Errorf("debt %s has negative accrued interest", debt.ID)
+	}
+
+	if debt.InterestRemainder.IsAnyNegative() {
+		return fmt.Errorf("debt %s has negative interest remainder", debt.ID)
+	}
+
+	if debt.AccruedFees.IsAnyNegative() {
+		return fmt.Errorf("debt %s has negative accrued fees", debt.ID)
+	}
+
+	if debt.Status != "" && !debt.Status.IsValid() {
+		return fmt.Errorf("debt %s has unknown status %s", debt.ID, debt.Status)
+	}
+
+	return nil
+}
+
+// isModuleOrPoolAddress yields true if the address is that of the module account, of one of
+// the given pools or of the pool that the denoms of the debt would have
+func isModuleOrPoolAddress(address sdk.AccAddress, debt Debt, pools []Pool) bool {
+	if address.Equals(supply.NewModuleAddress(ModuleName)) {
+		return true
+	}
+
+	for _, coin := range debt.Amount {
+		if address.Equals(PoolAddress(coin.Denom)) {
+			return true
+		}
+	}
+
+	for _, pool := range pools {
+		if address.Equals(pool.Address()) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// AutoIDPrefix starts the IDs that the keeper assigns from its sequence counter.
+// Clients cannot choose IDs in this namespace, so that they never collide
+const AutoIDPrefix = "seq-"
+
+// DefaultNextDebtSequence is the sequence number of the first assigned ID
+const DefaultNextDebtSequence uint64 = 1
+
+// FormatAutoID yields the ID assigned to the given sequence number
+func FormatAutoID(sequence uint64) string {
+	return AutoIDPrefix + strconv.FormatUint(sequence, 10)
+}
+
+// ParseAutoID yields the sequence number of an assigned ID, and false
+// if the ID was chosen by a client
+func ParseAutoID(id string) (uint64, bool) {
+	if !IsAutoID(id) {
+		return 0, false
+	}
+
+	sequence, err := strconv.ParseUint(strings.TrimPrefix(id, AutoIDPrefix), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return sequence, true
+}
+
+// IsAutoID yields true if the ID belongs to the namespace reserved to the keeper
+func IsAutoID(id string) bool {
+	return strings.HasPrefix(id, AutoIDPrefix)
+}
+
+// validateClientID checks the ID of a debt sent in a message: it can be empty,
+// so that the keeper assigns one, but cannot be in the reserved namespace
+func validateClientID(id string) error {
+	if IsAutoID(id) {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, fmt.Sprintf("IDs starting with %s are reserved", AutoIDPrefix))
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// interest methods
+const (
+	InterestSimple   = "simple"   // interest accrues on the outstanding principal only
+	InterestCompound = "compound" // interest accrues on outstanding principal and accrued interest
+)
+
+// units of the accrual period
+const (
+	PeriodBlocks  = "blocks"
+	PeriodSeconds = "seconds"
+)
+
+// InterestTerms describe how interest accrues on a debt. At the end of
+// each period, Rate is applied to the balance selected by Method
+type InterestTerms struct {
+	Rate       sdk.Dec `json:"rate"`
+	Method     string  `json:"method"`
+	Period     int64   `json:"period"`
+	PeriodUnit string  `json:"period_unit"`
+}
+
+func NewInterestTerms(rate sdk.Dec, method string, period int64, periodUnit string) InterestTerms {
+	return InterestTerms{
+		Rate:       rate,
+		Method:     method,
+		Period:     period,
+		PeriodUnit: periodUnit,
+	}
+}
+
+// NoInterest yields the terms of an interest-free debt
+func NoInterest() InterestTerms {
+	return NewInterestTerms(sdk.ZeroDec(), InterestSimple, 0, PeriodBlocks)
+}
+
+// IsZero yields true if no interest ever accrues with these terms
+func (terms InterestTerms) IsZero() bool {
+	return terms.Rate.IsNil() || terms.Rate.IsZero()
+}
+
+func (terms InterestTerms) Validate() error {
+	if terms.IsZero() {
+		return nil
+	}
+
+	if terms.Rate.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interest rate should be positive")
+	}
+
+	if terms.Method != InterestSimple && terms.Method != InterestCompound {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, fmt.Sprintf("Unknown interest method %s", terms.Method))
+	}
+
+	if terms.Period <= 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interest period should be positive")
+	}
+
+	if terms.PeriodUnit != PeriodBlocks && terms.PeriodUnit != PeriodSeconds {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, fmt.Sprintf("Unknown interest period unit %s", terms.PeriodUnit))
+	}
+
+	return nil
+}
+
+// InterestFor yields the interest that accrues in the given number of periods,
+// for the given outstanding principal and already accrued interest
+func (terms InterestTerms) InterestFor(principal, accrued sdk.Int, periods int64) sdk.Int {
+	return terms.ExactInterestFor(principal, accrued.ToDec(), periods).TruncateInt()
+}
+
+// ExactInterestFor is like InterestFor, without truncating the interest to whole coins,
+// so that the fractions of coin of each accrual can be carried to the next one
+func (terms InterestTerms) ExactInterestFor(principal sdk.Int, accrued sdk.Dec, periods int64) sdk.Dec {
+	if terms.IsZero() || periods <= 0 {
+		return sdk.ZeroDec()
+	}
+
+	if terms.Method == InterestCompound {
+		base := principal.ToDec().Add(accrued)
+		growth := sdk.OneDec().Add(terms.Rate).Power(uint64(periods)).Sub(sdk.OneDec())
+		return base.Mul(growth)
+	}
+
+	return principal.ToDec().Mul(terms.Rate).MulInt64(periods)
+}
+
+// MaxAnnualYield bounds the yields computed by AnnualYield, that saturate at it,
+// so that absurd terms are compared to the caps without overflowing
+var MaxAnnualYield = sdk.NewDec(1000000)
+
+// AnnualYield yields the interest that accrues in a year on each coin of principal,
+// converting blocks to time with the given number of blocks in a year
+func (terms InterestTerms) AnnualYield(blocksPerYear int64) sdk.Dec {
+	if terms.IsZero() || terms.Period <= 0 {
+		return sdk.ZeroDec()
+	}
+
+	units := unitsPerYear(terms.PeriodUnit, blocksPerYear)
+	periods := units / terms.Period
+	if terms.Method != InterestCompound || periods == 0 {
+		if terms.Rate.GT(MaxAnnualYield) {
+			return MaxAnnualYield
+		}
+		return sdk.MinDec(terms.Rate.MulInt64(units).QuoInt64(terms.Period), MaxAnnualYield)
+	}
+
+	growth := saturatingPower(sdk.OneDec().Add(terms.Rate), uint64(periods), MaxAnnualYield.Add(sdk.OneDec()))
+	return growth.Sub(sdk.OneDec())
+}
+
+// saturatingPower yields base to the given power, or max if that is larger.
+// The base must be at least 1, so that the partial products never decrease
+func saturatingPower(base sdk.Dec, power uint64, max sdk.Dec) sdk.Dec {
+	result := sdk.OneDec()
+	for power > 0 {
+		if power%2 == 1 {
+			if result = result.Mul(base); result.GT(max) {
+				return max
+			}
+		}
+
+		if power /= 2; power == 0 {
+			break
+		}
+
+		// a higher power of the base is still to be multiplied in
+		if base.GT(max) {
+			return max
+		}
+		base = base.Mul(base)
+	}
+
+	return result
+}
+
+// WithMaxAnnualYield yields the terms with their rate lowered, if needed, so that
+// they yield at most max in a year
+func (terms InterestTerms) WithMaxAnnualYield(max sdk.Dec, blocksPerYear int64) InterestTerms {
+	if !terms.AnnualYield(blocksPerYear).GT(max) {
+		return terms
+	}
+
+	// the yield grows with the rate, so we bisect for the largest rate within max
+	low, high := sdk.ZeroDec(), terms.Rate
+	for i := 0; i < 64; i++ {
+		candidate := terms
+		candidate.Rate = low.Add(high).QuoInt64(2)
+		if candidate.AnnualYield(blocksPerYear).GT(max) {
+			high = candidate.Rate
+		} else {
+			low = candidate.Rate
+		}
+	}
+
+	terms.Rate = low
+	return terms
+}
+
+// Equal yields true if both terms accrue the same interest
+func (terms InterestTerms) Equal(other InterestTerms) bool {
+	if terms.IsZero() || other.IsZero() {
+		return terms.IsZero() && other.IsZero()
+	}
+
+	return terms.Rate.Equal(other.Rate) &&
+		terms.Method == other.Method &&
+		terms.Period == other.Period &&
+		terms.PeriodUnit == other.PeriodUnit
+}
+
+func (terms InterestTerms) String() string {
+	if terms.IsZero() {
+		return "none"
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%s %s every %d %s",
+		terms.Rate,
+		terms.Method,
+		terms.Period,
+		terms.PeriodUnit))
+}
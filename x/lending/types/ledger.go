@@ -0,0 +1,61 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// EntryType is the kind of movement recorded in the ledger of a debt
+type EntryType string
+
+const (
+	EntryPayment     EntryType = "payment"     // paid to the creditor
+	EntryForgiveness EntryType = "forgiveness" // reduced by the creditor
+	EntryInterest    EntryType = "interest"    // accrued as interest
+	EntryPenalty     EntryType = "penalty"     // accrued as penalty interest while late
+	EntryFee         EntryType = "fee"         // charged as a fee
+	EntryTransfer    EntryType = "transfer"    // assigned to a new creditor
+)
+
+func (entryType EntryType) IsValid() bool {
+	switch entryType {
+	case EntryPayment, EntryForgiveness, EntryInterest, EntryPenalty, EntryFee, EntryTransfer:
+		return true
+	default:
+		return false
+	}
+}
+
+func (entryType EntryType) String() string {
+	return string(entryType)
+}
+
+// LedgerEntry records a movement of a debt, in the block and transaction
+// where it happened. Entries of a debt are numbered from 1 in order
+type LedgerEntry struct {
+	DebtID    string         `json:"debt_id"`
+	Sequence  uint64         `json:"sequence"`
+	Type      EntryType      `json:"type"`
+	Amount    DebtCoins      `json:"amount"`
+	Remaining DebtCoins      `json:"remaining"` // what the debtor owes after the movement
+	Creditor  sdk.AccAddress `json:"creditor"`  // the creditor after the movement
+	Height    int64          `json:"height"`
+	Time      time.Time      `json:"time"`
+	TxHash    string         `json:"tx_hash"` // empty for movements outside transactions, such as interest
+}
+
+func (entry LedgerEntry) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Debt ID: %s
+Sequence: %d
+Type: %s
+Amount: %s
+Remaining: %s
+Creditor: %s
+Height: %d
+Time: %s
+Tx hash: %s`, entry.DebtID, entry.Sequence, entry.Type, entry.Amount, entry.Remaining, entry.Creditor, entry.Height,
+		entry.Time, entry.TxHash))
+}
@@ -2,20 +2,58 @@ package types
 
 import (
 	"fmt"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/params"
 )
 
 // Default parameter namespace
 const (
 	DefaultParamspace = ModuleName
-	// TODO: Define your default parameters
+
+	DefaultMaxPriceAge = time.Hour
+
+	DefaultMinProposalExpiry  int64 = 1
+	DefaultDefaultGracePeriod       = 24 * time.Hour
+
+	DefaultArchiveRetention time.Duration = 0 // forever
+
+	DefaultTransferConsent = false
+
+	DefaultBlocksPerYear int64 = 6311520 // a block every 5 seconds
+
+	DefaultMaxInstallments int64 = 360 // monthly for thirty years
+)
+
+// default parameter values that are not constants
+var (
+	DefaultLiquidationRatio    = sdk.NewDecWithPrec(15, 1) // 150%
+	DefaultLiquidationDiscount = sdk.NewDecWithPrec(5, 2)  // 5%
+	DefaultMaxInterestRate     = sdk.OneDec()              // 100% a year
+	DefaultMaxLateFeeRate      = sdk.NewDecWithPrec(1, 1)  // 10% of the amount overdue
+	DefaultMaxPenaltyRate      = sdk.OneDec()              // 100% a year
 )
 
 // Parameter store keys
 var (
-	// TODO: Define your keys for the parameter store
-	// KeyParamName          = []byte("ParamName")
+	KeyOracles             = []byte("Oracles")
+	KeyMaxPriceAge         = []byte("MaxPriceAge")
+	KeyLiquidationRatio    = []byte("LiquidationRatio")
+	KeyLiquidationDiscount = []byte("LiquidationDiscount")
+	KeyAllowedDenoms       = []byte("AllowedDenoms")
+	KeyMaxDebtAmounts      = []byte("MaxDebtAmounts")
+	KeyMinProposalExpiry   = []byte("MinProposalExpiry")
+	KeyMaxInterestRate     = []byte("MaxInterestRate")
+	KeyDefaultGracePeriod  = []byte("DefaultGracePeriod")
+	KeyArchiveRetention    = []byte("ArchiveRetention")
+	KeyTransferConsent     = []byte("TransferConsent")
+	KeyMaxLateFeeRate      = []byte("MaxLateFeeRate")
+	KeyMaxPenaltyRate      = []byte("MaxPenaltyRate")
+	KeyRateModels          = []byte("RateModels")
+	KeyBlocksPerYear       = []byte("BlocksPerYear")
+	KeyMaxInstallments     = []byte("MaxInstallments")
+	KeyPoolCreators        = []byte("PoolCreators")
 )
 
 // ParamKeyTable for lending module
@@ -25,33 +63,461 @@ func ParamKeyTable() params.KeyTable {
 
 // Params - used for initializing default parameter for lending at genesis
 type Params struct {
-	// TODO: Add your Paramaters to the Paramter struct
-	// KeyParamName string `json:"key_param_name"`
+	Oracles             []sdk.AccAddress `json:"oracles"`              // the only addresses allowed to post prices
+	MaxPriceAge         time.Duration    `json:"max_price_age"`        // posted prices older than this are stale
+	LiquidationRatio    sdk.Dec          `json:"liquidation_ratio"`    // collateral value over owed value below which a debt can be liquidated
+	LiquidationDiscount sdk.Dec          `json:"liquidation_discount"` // discount on the collateral granted to liquidators
+	AllowedDenoms       []string         `json:"allowed_denoms"`       // the denoms that can be lent, all if empty
+	MaxDebtAmounts      sdk.Coins        `json:"max_debt_amounts"`     // the largest amount that can be lent, for each capped denom
+	MinProposalExpiry   int64            `json:"min_proposal_expiry"`  // the minimum number of blocks a proposal stays pending
+	MaxInterestRate     sdk.Dec          `json:"max_interest_rate"`    // the largest annual yield of interest
+	DefaultGracePeriod  time.Duration    `json:"default_grace_period"` // the grace period of debts with maturity that do not specify one
+	ArchiveRetention    time.Duration    `json:"archive_retention"`    // how long closed debts are kept in the archive, forever if 0
+	TransferConsent     bool             `json:"transfer_consent"`     // whether debts are transferred to a new creditor only with the consent of their debtor
+	MaxLateFeeRate      sdk.Dec          `json:"max_late_fee_rate"`    // the largest late fee, flat fee included, as a share of the amount overdue
+	MaxPenaltyRate      sdk.Dec          `json:"max_penalty_rate"`     // the largest annual yield of penalty interest
+	RateModels          []RateModel      `json:"rate_models"`          // the models that drive the rates of the pools of their denoms
+	BlocksPerYear       int64            `json:"blocks_per_year"`      // the expected number of blocks in a year, to convert annual rates
+	MaxInstallments     int64            `json:"max_installments"`     // the most installments of the repayment schedule of a debt
+	PoolCreators        []sdk.AccAddress `json:"pool_creators"`        // the only addresses allowed to create pools, that governance can change
 }
 
 // NewParams creates a new Params object
-func NewParams(/* TODO: Pass in the paramters*/) Params {
+func NewParams(oracles []sdk.AccAddress, maxPriceAge time.Duration, liquidationRatio, liquidationDiscount sdk.Dec,
+	allowedDenoms []string, maxDebtAmounts sdk.Coins, minProposalExpiry int64, maxInterestRate sdk.Dec,
+	defaultGracePeriod, archiveRetention time.Duration, transferConsent bool, maxLateFeeRate, maxPenaltyRate sdk.Dec,
+	rateModels []RateModel, blocksPerYear, maxInstallments int64, poolCreators []sdk.AccAddress) Params {
 	return Params{
-		// TODO: Create your Params Type
+		Oracles:             oracles,
+		MaxPriceAge:         maxPriceAge,
+		LiquidationRatio:    liquidationRatio,
+		LiquidationDiscount: liquidationDiscount,
+		AllowedDenoms:       allowedDenoms,
+		MaxDebtAmounts:      maxDebtAmounts,
+		MinProposalExpiry:   minProposalExpiry,
+		MaxInterestRate:     maxInterestRate,
+		DefaultGracePeriod:  defaultGracePeriod,
+		ArchiveRetention:    archiveRetention,
+		TransferConsent:     transferConsent,
+		MaxLateFeeRate:      maxLateFeeRate,
+		MaxPenaltyRate:      maxPenaltyRate,
+		RateModels:          rateModels,
+		BlocksPerYear:       blocksPerYear,
+		MaxInstallments:     maxInstallments,
+		PoolCreators:        poolCreators,
 	}
 }
 
 // String implements the stringer interface for Params
 func (p Params) String() string {
-	return fmt.Sprintf(`
-	// TODO: Return all the params as a string
-	`, )
+	return fmt.Sprintf(`Params:
+  Oracles:              %s
+  Max price age:        %s
+  Liquidation ratio:    %s
+  Liquidation discount: %s
+  Allowed denoms:       %s
+  Max debt amounts:     %s
+  Min proposal expiry:  %d
+  Max interest rate:    %s
+  Default grace period: %s
+  Archive retention:    %s
+  Transfer consent:     %t
+  Max late fee rate:    %s
+  Max penalty rate:     %s
+  Rate models:          %s
+  Blocks per year:      %d
+  Max installments:     %d
+  Pool creators:        %s`,
+		p.Oracles,
+		p.MaxPriceAge,
+		p.LiquidationRatio,
+		p.LiquidationDiscount,
+		p.AllowedDenoms,
+		p.MaxDebtAmounts,
+		p.MinProposalExpiry,
+		p.MaxInterestRate,
+		p.DefaultGracePeriod,
+		p.ArchiveRetention,
+		p.TransferConsent,
+		p.MaxLateFeeRate,
+		p.MaxPenaltyRate,
+		p.RateModels,
+		p.BlocksPerYear,
+		p.MaxInstallments,
+		p.PoolCreators)
 }
 
 // ParamSetPairs - Implements params.ParamSet
 func (p *Params) ParamSetPairs() params.ParamSetPairs {
 	return params.ParamSetPairs{
-		// TODO: Pair your key with the param
-		// params.NewParamSetPair(KeyParamName, &p.ParamName),
+		params.NewParamSetPair(KeyOracles, &p.Oracles, validateOracles),
+		params.NewParamSetPair(KeyMaxPriceAge, &p.MaxPriceAge, validateMaxPriceAge),
+		params.NewParamSetPair(KeyLiquidationRatio, &p.LiquidationRatio, validateLiquidationRatio),
+		params.NewParamSetPair(KeyLiquidationDiscount, &p.LiquidationDiscount, validateLiquidationDiscount),
+		params.NewParamSetPair(KeyAllowedDenoms, &p.AllowedDenoms, validateAllowedDenoms),
+		params.NewParamSetPair(KeyMaxDebtAmounts, &p.MaxDebtAmounts, validateMaxDebtAmounts),
+		params.NewParamSetPair(KeyMinProposalExpiry, &p.MinProposalExpiry, validateMinProposalExpiry),
+		params.NewParamSetPair(KeyMaxInterestRate, &p.MaxInterestRate, validateMaxInterestRate),
+		params.NewParamSetPair(KeyDefaultGracePeriod, &p.DefaultGracePeriod, validateDefaultGracePeriod),
+		params.NewParamSetPair(KeyArchiveRetention, &p.ArchiveRetention, validateArchiveRetention),
+		params.NewParamSetPair(KeyTransferConsent, &p.TransferConsent, validateTransferConsent),
+		params.NewParamSetPair(KeyMaxLateFeeRate, &p.MaxLateFeeRate, validateMaxLateFeeRate),
+		params.NewParamSetPair(KeyMaxPenaltyRate, &p.MaxPenaltyRate, validateMaxPenaltyRate),
+		params.NewParamSetPair(KeyRateModels, &p.RateModels, validateRateModels),
+		params.NewParamSetPair(KeyBlocksPerYear, &p.BlocksPerYear, validateBlocksPerYear),
+		params.NewParamSetPair(KeyMaxInstallments, &p.MaxInstallments, validateMaxInstallments),
+		params.NewParamSetPair(KeyPoolCreators, &p.PoolCreators, validatePoolCreators),
+	}
+}
+
+// IsOracle yields true if the address is allowed to post prices
+func (p Params) IsOracle(address sdk.AccAddress) bool {
+	for _, oracle := range p.Oracles {
+		if oracle.Equals(address) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPoolCreator yields true if the address is allowed to create pools
+func (p Params) IsPoolCreator(address sdk.AccAddress) bool {
+	for _, creator := range p.PoolCreators {
+		if creator.Equals(address) {
+			return true
+		}
 	}
+
+	return false
+}
+
+// IsAllowedDenom yields true if debts can be denominated in the given denom
+func (p Params) IsAllowedDenom(denom string) bool {
+	if len(p.AllowedDenoms) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.AllowedDenoms {
+		if allowed == denom {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RateModel yields the model that drives the rates of the pool of the given denom, if any
+func (p Params) RateModel(denom string) (RateModel, bool) {
+	for _, model := range p.RateModels {
+		if model.Denom == denom {
+			return model, true
+		}
+	}
+
+	return RateModel{}, false
+}
+
+// Validate checks that all parameters have acceptable values
+func (p Params) Validate() error {
+	if err := validateOracles(p.Oracles); err != nil {
+		return err
+	}
+	if err := validateMaxPriceAge(p.MaxPriceAge); err != nil {
+		return err
+	}
+	if err := validateLiquidationRatio(p.LiquidationRatio); err != nil {
+		return err
+	}
+	if err := validateLiquidationDiscount(p.LiquidationDiscount); err != nil {
+		return err
+	}
+	if err := validateAllowedDenoms(p.AllowedDenoms); err != nil {
+		return err
+	}
+	if err := validateMaxDebtAmounts(p.MaxDebtAmounts); err != nil {
+		return err
+	}
+	if err := validateMinProposalExpiry(p.MinProposalExpiry); err != nil {
+		return err
+	}
+	if err := validateMaxInterestRate(p.MaxInterestRate); err != nil {
+		return err
+	}
+
+	if err := validateDefaultGracePeriod(p.DefaultGracePeriod); err != nil {
+		return err
+	}
+
+	if err := validateArchiveRetention(p.ArchiveRetention); err != nil {
+		return err
+	}
+
+	if err := validateTransferConsent(p.TransferConsent); err != nil {
+		return err
+	}
+
+	if err := validateMaxLateFeeRate(p.MaxLateFeeRate); err != nil {
+		return err
+	}
+
+	if err := validateMaxPenaltyRate(p.MaxPenaltyRate); err != nil {
+		return err
+	}
+
+	if err := validateRateModels(p.RateModels); err != nil {
+		return err
+	}
+
+	if err := validateBlocksPerYear(p.BlocksPerYear); err != nil {
+		return err
+	}
+
+	if err := validateMaxInstallments(p.MaxInstallments); err != nil {
+		return err
+	}
+
+	return validatePoolCreators(p.PoolCreators)
 }
 
 // DefaultParams defines the parameters for this module
 func DefaultParams() Params {
-	return NewParams( /* TODO: Pass in your default Params */ )
+	return NewParams([]sdk.AccAddress{}, DefaultMaxPriceAge, DefaultLiquidationRatio, DefaultLiquidationDiscount,
+		[]string{}, sdk.NewCoins(), DefaultMinProposalExpiry, DefaultMaxInterestRate, DefaultDefaultGracePeriod,
+		DefaultArchiveRetention, DefaultTransferConsent, DefaultMaxLateFeeRate, DefaultMaxPenaltyRate,
+		[]RateModel{}, DefaultBlocksPerYear, DefaultMaxInstallments, []sdk.AccAddress{})
+}
+
+func validateOracles(i interface{}) error {
+	oracles, ok := i.([]sdk.AccAddress)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	for _, oracle := range oracles {
+		if oracle.Empty() {
+			return fmt.Errorf("oracle address cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+func validateMaxPriceAge(i interface{}) error {
+	age, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if age <= 0 {
+		return fmt.Errorf("max price age must be positive: %s", age)
+	}
+
+	return nil
+}
+
+func validateLiquidationRatio(i interface{}) error {
+	ratio, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if ratio.IsNil() || ratio.LT(sdk.OneDec()) {
+		return fmt.Errorf("liquidation ratio must be at least 1: %s", ratio)
+	}
+
+	return nil
+}
+
+func validateLiquidationDiscount(i interface{}) error {
+	discount, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if discount.IsNil() || discount.IsNegative() || discount.GTE(sdk.OneDec()) {
+		return fmt.Errorf("liquidation discount must be in [0, 1): %s", discount)
+	}
+
+	return nil
+}
+
+func validateAllowedDenoms(i interface{}) error {
+	denoms, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool)
+	for _, denom := range denoms {
+		if err := sdk.ValidateDenom(denom); err != nil {
+			return err
+		}
+		if seen[denom] {
+			return fmt.Errorf("duplicate allowed denom %s", denom)
+		}
+		seen[denom] = true
+	}
+
+	return nil
+}
+
+func validateMaxDebtAmounts(i interface{}) error {
+	amounts, ok := i.(sdk.Coins)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if !amounts.Empty() && !amounts.IsValid() {
+		return fmt.Errorf("invalid max debt amounts: %s", amounts)
+	}
+
+	return nil
+}
+
+func validateMinProposalExpiry(i interface{}) error {
+	expiry, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if expiry <= 0 {
+		return fmt.Errorf("min proposal expiry must be positive: %d", expiry)
+	}
+
+	return nil
+}
+
+func validateMaxInterestRate(i interface{}) error {
+	rate, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if rate.IsNil() || rate.IsNegative() {
+		return fmt.Errorf("max interest rate cannot be negative: %s", rate)
+	}
+
+	return nil
+}
+
+func validateDefaultGracePeriod(i interface{}) error {
+	period, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if period < 0 {
+		return fmt.Errorf("default grace period cannot be negative: %s", period)
+	}
+
+	return nil
+}
+
+func validateArchiveRetention(i interface{}) error {
+	retention, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if retention < 0 {
+		return fmt.Errorf("archive retention cannot be negative: %s", retention)
+	}
+
+	return nil
+}
+
+func validateTransferConsent(i interface{}) error {
+	if _, ok := i.(bool); !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	return nil
+}
+
+func validateMaxLateFeeRate(i interface{}) error {
+	rate, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if rate.IsNil() || rate.IsNegative() {
+		return fmt.Errorf("max late fee rate cannot be negative: %s", rate)
+	}
+
+	return nil
+}
+
+func validateMaxPenaltyRate(i interface{}) error {
+	rate, ok := i.(sdk.Dec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if rate.IsNil() || rate.IsNegative() {
+		return fmt.Errorf("max penalty rate cannot be negative: %s", rate)
+	}
+
+	return nil
+}
+
+func validateRateModels(i interface{}) error {
+	models, ok := i.([]RateModel)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	seen := make(map[string]bool)
+	for _, model := range models {
+		if err := model.Validate(); err != nil {
+			return err
+		}
+		if seen[model.Denom] {
+			return fmt.Errorf("duplicate rate model for %s", model.Denom)
+		}
+		seen[model.Denom] = true
+	}
+
+	return nil
+}
+
+func validateBlocksPerYear(i interface{}) error {
+	blocks, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if blocks <= 0 {
+		return fmt.Errorf("blocks per year must be positive: %d", blocks)
+	}
+
+	return nil
+}
+
+func validateMaxInstallments(i interface{}) error {
+	max, ok := i.(int64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	if max <= 0 || max > MaxScheduleInstallments {
+		return fmt.Errorf("max installments must be between 1 and %d: %d", MaxScheduleInstallments, max)
+	}
+
+	return nil
+}
+
+func validatePoolCreators(i interface{}) error {
+	creators, ok := i.([]sdk.AccAddress)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	for _, creator := range creators {
+		if creator.Empty() {
+			return fmt.Errorf("pool creator address cannot be empty")
+		}
+	}
+
+	return nil
 }
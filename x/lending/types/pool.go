@@ -0,0 +1,169 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+)
+
+// ShareDenomPrefix prefixes the denom of a pool in the denom of its share tokens
+const ShareDenomPrefix = "lp"
+
+// Pool is liquidity in a denom that many lenders deposit and borrowers draw from.
+// Lenders receive share tokens, minted by the module account, in proportion to their
+// deposit and to the value of the pool, that grows with the interest owed by its
+// borrowers. The liquidity is held by the account of the pool, that no key controls,
+// and the loans are collateralized debts whose creditor is that account
+type Pool struct {
+	Denom       string        `json:"denom"`
+	Interest    InterestTerms `json:"interest"` // the interest of the loans drawn from the pool
+	TotalShares sdk.Int       `json:"total_shares"`
+}
+
+// NewPool yields a pool without deposits yet
+func NewPool(denom string, interest InterestTerms) Pool {
+	return Pool{
+		Denom:       denom,
+		Interest:    interest,
+		TotalShares: sdk.ZeroInt(),
+	}
+}
+
+// PoolAddress yields the address of the account that holds the liquidity of the pool of a denom
+func PoolAddress(denom string) sdk.AccAddress {
+	return supply.NewModuleAddress(ModuleName + "/pool/" + denom)
+}
+
+// Address yields the address of the account that holds the liquidity of the pool
+func (p Pool) Address() sdk.AccAddress {
+	return PoolAddress(p.Denom)
+}
+
+// PoolLoan records that a debt was drawn from the pool of a denom, so that
+// only the loans of the pool count towards what it has lent
+type PoolLoan struct {
+	Denom  string `json:"denom"`
+	DebtID string `json:"debt_id"`
+}
+
+func NewPoolLoan(denom, debtID string) PoolLoan {
+	return PoolLoan{
+		Denom:  denom,
+		DebtID: debtID,
+	}
+}
+
+// ShareDenom yields the denom of the share tokens of the pool
+func (p Pool) ShareDenom() string {
+	return ShareDenomPrefix + p.Denom
+}
+
+func (p Pool) Validate() error {
+	if err := p.ValidateNew(); err != nil {
+		return err
+	}
+
+	if p.TotalShares == (sdk.Int{}) || p.TotalShares.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Total shares can't be negative")
+	}
+
+	return nil
+}
+
+// ValidateNew validates a pool sent in a message, whose shares are not set yet
+func (p Pool) ValidateNew() error {
+	if err := sdk.ValidateDenom(p.Denom); err != nil {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, err.Error())
+	}
+
+	if err := sdk.ValidateDenom(p.ShareDenom()); err != nil {
+		return sdkErr.Wrapf(sdkErr.ErrInvalidCoins, "the share denom of a pool of %s would be invalid: %s", p.Denom, err)
+	}
+
+	return p.Interest.Validate()
+}
+
+func (p Pool) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Denom: %s
+                Interest: %s
+                Total shares: %s%s`,
+		p.Denom,
+		p.Interest,
+		p.TotalShares, p.ShareDenom()))
+}
+
+// PoolState is a pool together with what it holds and what is lent from it
+type PoolState struct {
+	Pool        Pool           `json:"pool"`
+	Address     sdk.AccAddress `json:"address"`     // the creditor of the loans drawn from the pool
+	Liquidity   sdk.Int        `json:"liquidity"`   // what can be withdrawn or borrowed now
+	Borrowed    sdk.Int        `json:"borrowed"`    // what the borrowers owe to the pool, accrued interest included
+	ShareValue  sdk.Dec        `json:"share_value"` // what a share is worth, in the denom of the pool
+	Utilization sdk.Dec        `json:"utilization"` // the share of the value of the pool that is lent
+}
+
+// the shares and coins that every pool holds on top of its deposits. They keep the first
+// depositor from inflating the value of a share with a donation to the pool account,
+// so that later deposits round down to no shares: most of the donation would go to
+// the virtual shares instead
+var (
+	virtualShares = sdk.NewInt(1000)
+	virtualValue  = sdk.OneInt()
+)
+
+func NewPoolState(pool Pool, liquidity, borrowed sdk.Int) PoolState {
+	state := PoolState{
+		Pool:        pool,
+		Address:     pool.Address(),
+		Liquidity:   liquidity,
+		Borrowed:    borrowed,
+		Utilization: sdk.ZeroDec(),
+	}
+
+	if value := state.Value(); value.IsPositive() {
+		state.Utilization = borrowed.ToDec().QuoInt(value)
+	}
+	state.ShareValue = state.Value().Add(virtualValue).ToDec().QuoInt(pool.TotalShares.Add(virtualShares))
+
+	return state
+}
+
+// Value yields what the pool is worth: its liquidity and what is owed to it
+func (s PoolState) Value() sdk.Int {
+	return s.Liquidity.Add(s.Borrowed)
+}
+
+// SharesFor yields the shares that a deposit of the given amount is worth.
+// The first deposit gets a thousand shares for each coin
+func (s PoolState) SharesFor(amount sdk.Int) sdk.Int {
+	return amount.Mul(s.Pool.TotalShares.Add(virtualShares)).Quo(s.Value().Add(virtualValue))
+}
+
+// AmountFor yields what the given shares are worth
+func (s PoolState) AmountFor(shares sdk.Int) sdk.Int {
+	return shares.Mul(s.Value().Add(virtualValue)).Quo(s.Pool.TotalShares.Add(virtualShares))
+}
+
+// PoolDeposit is the position of a lender in a pool
+type PoolDeposit struct {
+	Depositor sdk.AccAddress `json:"depositor"`
+	Denom     string         `json:"denom"`
+	Shares    sdk.Int        `json:"shares"`
+	Value     sdk.Int        `json:"value"`     // what the shares are worth
+	Available sdk.Int        `json:"available"` // what can be withdrawn now, given the liquidity of the pool
+}
+
+func NewPoolDeposit(depositor sdk.AccAddress, state PoolState, shares sdk.Int) PoolDeposit {
+	value := state.AmountFor(shares)
+
+	return PoolDeposit{
+		Depositor: depositor,
+		Denom:     state.Pool.Denom,
+		Shares:    shares,
+		Value:     value,
+		Available: sdk.MinInt(value, state.Liquidity),
+	}
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PostedPrice is the price of a denom, in a common reference unit,
+// as reported by an oracle at a given time
+type PostedPrice struct {
+	Denom  string         `json:"denom"`
+	Oracle sdk.AccAddress `json:"oracle"`
+	Price  sdk.Dec        `json:"price"`
+	Time   time.Time      `json:"time"`
+}
+
+func NewPostedPrice(denom string, oracle sdk.AccAddress, price sdk.Dec, postTime time.Time) PostedPrice {
+	return PostedPrice{
+		Denom:  denom,
+		Oracle: oracle,
+		Price:  price,
+		Time:   postTime,
+	}
+}
+
+// IsStale yields true if the price was posted more than maxAge before now
+func (price PostedPrice) IsStale(now time.Time, maxAge time.Duration) bool {
+	return now.Sub(price.Time) > maxAge
+}
+
+func (price PostedPrice) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Denom: %s
+Oracle: %s
+Price: %s
+Time: %s`, price.Denom, price.Oracle, price.Price, price.Time))
+}
+
+// CurrentPrice is the price of a denom aggregated from the fresh prices of all oracles
+type CurrentPrice struct {
+	Denom string  `json:"denom"`
+	Price sdk.Dec `json:"price"`
+}
+
+func NewCurrentPrice(denom string, price sdk.Dec) CurrentPrice {
+	return CurrentPrice{
+		Denom: denom,
+		Price: price,
+	}
+}
+
+func (price CurrentPrice) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Denom: %s
+Price: %s`, price.Denom, price.Price))
+}
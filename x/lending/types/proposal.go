@@ -0,0 +1,40 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultProposalExpiry is the number of blocks a proposal stays pending
+// when its creditor does not specify an expiry
+const DefaultProposalExpiry int64 = 100
+
+// DebtProposal is a debt offered by a creditor that becomes active
+// only once its debtor accepts it
+type DebtProposal struct {
+	Debt             Debt  `json:"debt"`
+	ExpirationHeight int64 `json:"expiration_height"`
+}
+
+func NewDebtProposal(debt Debt, expirationHeight int64) DebtProposal {
+	return DebtProposal{
+		Debt:             debt,
+		ExpirationHeight: expirationHeight,
+	}
+}
+
+// IsExpired yields true if the proposal cannot be accepted anymore at the given height
+func (p DebtProposal) IsExpired(height int64) bool {
+	return height > p.ExpirationHeight
+}
+
+func (p DebtProposal) Validate() error {
+	return p.Debt.Validate()
+}
+
+func (p DebtProposal) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`%s
+                Expiration height: %d`,
+		p.Debt,
+		p.ExpirationHeight))
+}
@@ -1,8 +1,168 @@
 package types
 
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
 // Query endpoints supported by the lending querier
 const (
+	QueryDebt          = "debt"
 	QueryAllDebts      = "debts"
 	QueryDebtorDebts   = "debtordebts"
 	QueryCreditorDebts = "creditordebts"
-)
\ No newline at end of file
+	QueryDebtHistory   = "history"
+	QueryDebtLedger    = "ledger"
+	QueryDebtSchedule  = "schedule"
+
+	QueryAllProposals      = "proposals"
+	QueryDebtorProposals   = "debtorproposals"
+	QueryCreditorProposals = "creditorproposals"
+
+	QueryTransfers = "transfers"
+
+	QueryCreditLine          = "creditline"
+	QueryAllCreditLines      = "creditlines"
+	QueryBorrowerCreditLines = "borrowercreditlines"
+	QueryLenderCreditLines   = "lendercreditlines"
+
+	QueryPositions = "positions"
+
+	QueryPool         = "pool"
+	QueryAllPools     = "pools"
+	QueryPoolDeposits = "pooldeposits"
+	QueryPoolRates    = "poolrates"
+
+	QueryPrice  = "price"
+	QueryParams = "params"
+)
+
+// DefaultQueryLimit is the number of results of a page when the query does not specify it
+const DefaultQueryLimit = 100
+
+// QueryDebtsParams are the pagination and filters of the debts queries.
+// Zero values, including zero amounts, mean no filter. For debts in many
+// denoms, the amount bounds apply to the filtered denom or to any denom
+type QueryDebtsParams struct {
+	Page         int            `json:"page"`
+	Limit        int            `json:"limit"`
+	Status       DebtStatus     `json:"status"`
+	Denom        string         `json:"denom"`
+	MinAmount    sdk.Int        `json:"min_amount"`
+	MaxAmount    sdk.Int        `json:"max_amount"`
+	Counterparty sdk.AccAddress `json:"counterparty"`
+}
+
+func NewQueryDebtsParams(page, limit int) QueryDebtsParams {
+	return QueryDebtsParams{
+		Page:  page,
+		Limit: limit,
+	}
+}
+
+func (params QueryDebtsParams) Validate() error {
+	if params.Page < 0 || params.Limit < 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Page and limit can't be negative")
+	}
+
+	if params.Status != "" && !params.Status.IsValid() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, fmt.Sprintf("Unknown debt status %s", params.Status))
+	}
+
+	if hasBound(params.MinAmount) && params.MinAmount.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Min amount can't be negative")
+	}
+
+	if hasBound(params.MinAmount) && hasBound(params.MaxAmount) && params.MinAmount.GT(params.MaxAmount) {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Min amount can't be greater than max amount")
+	}
+
+	return nil
+}
+
+// Matches yields true if the debt satisfies all the filters. The counterparty
+// is the other party of the debt with respect to the given address, or any
+// of its parties if the address is empty
+func (params QueryDebtsParams) Matches(debt Debt, address sdk.AccAddress) bool {
+	if params.Status != "" && debt.Status != params.Status {
+		return false
+	}
+
+	if params.Denom != "" && !debt.Amount.HasDenom(params.Denom) {
+		return false
+	}
+
+	if !params.matchesAmount(debt.Amount) {
+		return false
+	}
+
+	if !params.Counterparty.Empty() {
+		switch {
+		case address.Empty():
+			return debt.Debtor.Equals(params.Counterparty) || debt.Creditor.Equals(params.Counterparty)
+		case debt.Debtor.Equals(address):
+			return debt.Creditor.Equals(params.Counterparty)
+		default:
+			return debt.Debtor.Equals(params.Counterparty)
+		}
+	}
+
+	return true
+}
+
+// MatchesArchived is like Matches for a closed debt. Since nothing
+// is outstanding anymore, the amount bounds apply to its principal
+func (params QueryDebtsParams) MatchesArchived(archived ArchivedDebt, address sdk.AccAddress) bool {
+	debt := archived.Debt
+	debt.Amount = debt.Principal
+	return params.Matches(debt, address)
+}
+
+// matchesAmount yields true if the amount in the filtered denom is within the bounds or,
+// without a denom filter, if the amount in any of the denoms of the debt is
+func (params QueryDebtsParams) matchesAmount(amount DebtCoins) bool {
+	for _, coin := range amount {
+		if params.Denom != "" && coin.Denom != params.Denom {
+			continue
+		}
+
+		if hasBound(params.MinAmount) && coin.Amount.LT(params.MinAmount) {
+			continue
+		}
+
+		if hasBound(params.MaxAmount) && coin.Amount.GT(params.MaxAmount) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// hasBound yields true if the amount is set and not zero
+func hasBound(amount sdk.Int) bool {
+	return amount != (sdk.Int{}) && !amount.IsZero()
+}
+
+// Positions are the debts and the credit lines of an address on both of their sides,
+// so that what it owes and what it is owed can be read from a single query
+type Positions struct {
+	Address    sdk.AccAddress `json:"address"`
+	AsDebtor   []Debt         `json:"as_debtor"`
+	AsCreditor []Debt         `json:"as_creditor"`
+	AsBorrower []CreditLine   `json:"as_borrower"`
+	AsLender   []CreditLine   `json:"as_lender"`
+}
+
+func NewPositions(address sdk.AccAddress, asDebtor, asCreditor []Debt, asBorrower, asLender []CreditLine) Positions {
+	return Positions{
+		Address:    address,
+		AsDebtor:   asDebtor,
+		AsCreditor: asCreditor,
+		AsBorrower: asBorrower,
+		AsLender:   asLender,
+	}
+}
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// kinds of interest rate models
+const (
+	RateModelLinear   = "linear" // the rate grows with utilization at a constant slope
+	RateModelJumpRate = "jump"   // the rate grows at a steeper slope beyond the optimal utilization
+)
+
+// SecondsPerYear converts annual rates into rates per period of seconds
+const SecondsPerYear int64 = 365 * 24 * 60 * 60
+
+// RateModel yields the annual borrow rate of the pool of a denom from its utilization,
+// that is, the share of the liquidity committed by the lenders that is lent.
+// The rate is BaseRate plus Slope times the utilization, up to OptimalUtilization.
+// Beyond it, the rate of jump rate models grows at JumpSlope instead, in order to
+// draw new deposits and repayments before the pool runs dry
+type RateModel struct {
+	Denom              string  `json:"denom"`
+	Kind               string  `json:"kind"`
+	BaseRate           sdk.Dec `json:"base_rate"`
+	Slope              sdk.Dec `json:"slope"`
+	OptimalUtilization sdk.Dec `json:"optimal_utilization"` // the kink of jump rate models
+	JumpSlope          sdk.Dec `json:"jump_slope"`          // the slope of jump rate models beyond the kink
+}
+
+// NewLinearRateModel yields a model whose rate grows with utilization at a constant slope
+func NewLinearRateModel(denom string, baseRate, slope sdk.Dec) RateModel {
+	return RateModel{
+		Denom:              denom,
+		Kind:               RateModelLinear,
+		BaseRate:           baseRate,
+		Slope:              slope,
+		OptimalUtilization: sdk.OneDec(),
+		JumpSlope:          slope,
+	}
+}
+
+// NewJumpRateModel yields a model whose rate grows at jumpSlope beyond the optimal utilization
+func NewJumpRateModel(denom string, baseRate, slope, optimalUtilization, jumpSlope sdk.Dec) RateModel {
+	return RateModel{
+		Denom:              denom,
+		Kind:               RateModelJumpRate,
+		BaseRate:           baseRate,
+		Slope:              slope,
+		OptimalUtilization: optimalUtilization,
+		JumpSlope:          jumpSlope,
+	}
+}
+
+// BorrowRate yields the annual rate of the loans at the given utilization
+func (m RateModel) BorrowRate(utilization sdk.Dec) sdk.Dec {
+	if m.Kind != RateModelJumpRate || utilization.LTE(m.OptimalUtilization) {
+		return m.BaseRate.Add(m.Slope.Mul(utilization))
+	}
+
+	kink := m.BaseRate.Add(m.Slope.Mul(m.OptimalUtilization))
+	return kink.Add(m.JumpSlope.Mul(utilization.Sub(m.OptimalUtilization)))
+}
+
+// Terms yields the given terms with the borrow rate at the given utilization, converted
+// to their period. Terms without a period get those of ModelTerms
+func (m RateModel) Terms(terms InterestTerms, utilization sdk.Dec, blocksPerYear int64) InterestTerms {
+	if terms.Period <= 0 {
+		terms = ModelTerms()
+	}
+
+	terms.Rate = m.BorrowRate(utilization).MulInt64(terms.Period).QuoInt64(unitsPerYear(terms.PeriodUnit, blocksPerYear))
+	return terms
+}
+
+// ModelTerms are the terms of the loans of a pool with a rate model, if the pool
+// does not specify how interest accrues: compounded at each block
+func ModelTerms() InterestTerms {
+	return NewInterestTerms(sdk.ZeroDec(), InterestCompound, 1, PeriodBlocks)
+}
+
+// unitsPerYear yields the number of blocks or seconds in a year
+func unitsPerYear(periodUnit string, blocksPerYear int64) int64 {
+	if periodUnit == PeriodSeconds {
+		return SecondsPerYear
+	}
+
+	return blocksPerYear
+}
+
+func (m RateModel) Validate() error {
+	if err := sdk.ValidateDenom(m.Denom); err != nil {
+		return sdkErr.Wrap(sdkErr.ErrInvalidCoins, err.Error())
+	}
+
+	return m.ValidateCurve()
+}
+
+// ValidateCurve validates the rates of the model, regardless of its denom
+func (m RateModel) ValidateCurve() error {
+	if m.Kind != RateModelLinear && m.Kind != RateModelJumpRate {
+		return sdkErr.Wrapf(sdkErr.ErrInvalidRequest, "Unknown rate model %s", m.Kind)
+	}
+
+	if m.BaseRate.IsNil() || m.BaseRate.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Base rate can't be negative")
+	}
+
+	if m.Slope.IsNil() || m.Slope.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Slope can't be negative")
+	}
+
+	if m.Kind == RateModelLinear {
+		return nil
+	}
+
+	if m.OptimalUtilization.IsNil() || !m.OptimalUtilization.IsPositive() || m.OptimalUtilization.GT(sdk.OneDec()) {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Optimal utilization must be in (0, 1]")
+	}
+
+	if m.JumpSlope.IsNil() || m.JumpSlope.IsNegative() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Jump slope can't be negative")
+	}
+
+	return nil
+}
+
+func (m RateModel) String() string {
+	if m.Kind != RateModelJumpRate {
+		return fmt.Sprintf("%s: %s + %s * utilization", m.Denom, m.BaseRate, m.Slope)
+	}
+
+	return fmt.Sprintf("%s: %s + %s * utilization, then %s beyond %s",
+		m.Denom, m.BaseRate, m.Slope, m.JumpSlope, m.OptimalUtilization)
+}
+
+// PoolRates are the rates of a pool at some utilization
+type PoolRates struct {
+	Denom       string        `json:"denom"`
+	Model       string        `json:"model"` // the kind of rate model of the pool, empty if its rate is fixed
+	Utilization sdk.Dec       `json:"utilization"`
+	Interest    InterestTerms `json:"interest"`   // the terms of the loans, with the rate per period
+	BorrowAPY   sdk.Dec       `json:"borrow_apy"` // what the loans cost in a year
+	SupplyAPY   sdk.Dec       `json:"supply_apy"` // what the deposits earn in a year: the interest on what is lent, spread over the pool
+}
+
+// NewPoolRates yields the rates of a pool whose loans accrue interest with the given terms
+func NewPoolRates(denom, model string, terms InterestTerms, utilization sdk.Dec, blocksPerYear int64) PoolRates {
+	borrowAPY := terms.AnnualYield(blocksPerYear)
+
+	return PoolRates{
+		Denom:       denom,
+		Model:       model,
+		Utilization: utilization,
+		Interest:    terms,
+		BorrowAPY:   borrowAPY,
+		SupplyAPY:   borrowAPY.Mul(utilization),
+	}
+}
+
+// CalculateRates yields the rates of a pool with the given model and terms at the given
+// utilization. It needs no state, so that it can be used for what-if analysis
+func CalculateRates(model RateModel, terms InterestTerms, utilization sdk.Dec, blocksPerYear int64) PoolRates {
+	return NewPoolRates(model.Denom, model.Kind, model.Terms(terms, utilization, blocksPerYear), utilization, blocksPerYear)
+}
+
+func (r PoolRates) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Denom: %s
+                Model: %s
+                Utilization: %s
+                Interest: %s
+                Borrow APY: %s
+                Supply APY: %s`,
+		r.Denom,
+		r.Model,
+		r.Utilization,
+		r.Interest,
+		r.BorrowAPY,
+		r.SupplyAPY))
+}
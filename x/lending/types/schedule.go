@@ -0,0 +1,411 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// ScheduleType is how the repayment schedule of a debt is generated
+type ScheduleType string
+
+const (
+	ScheduleNone    ScheduleType = ""        // a lump-sum debt, without installments
+	ScheduleEqual   ScheduleType = "equal"   // equal installments of principal and interest
+	ScheduleBalloon ScheduleType = "balloon" // interest-only installments, then the whole principal with the last one
+	ScheduleCustom  ScheduleType = "custom"  // installments of the given amounts at the given times
+)
+
+func (t ScheduleType) IsValid() bool {
+	switch t {
+	case ScheduleNone, ScheduleEqual, ScheduleBalloon, ScheduleCustom:
+		return true
+	default:
+		return false
+	}
+}
+
+// InstallmentStatus is the stage of an installment of a repayment schedule
+type InstallmentStatus string
+
+const (
+	InstallmentPending  InstallmentStatus = "pending"  // not fully paid, but not yet due
+	InstallmentLate     InstallmentStatus = "late"     // past its due time and not fully paid
+	InstallmentPaid     InstallmentStatus = "paid"     // fully paid, possibly late
+	InstallmentForgiven InstallmentStatus = "forgiven" // settled by forgiveness, maybe after partial payments
+)
+
+// InstallmentDue is an installment of a custom schedule: what is due by when
+type InstallmentDue struct {
+	DueTime time.Time `json:"due_time"`
+	Amount  DebtCoins `json:"amount"`
+}
+
+func NewInstallmentDue(dueTime time.Time, amount sdk.Coins) InstallmentDue {
+	return InstallmentDue{
+		DueTime: dueTime,
+		Amount:  NewDebtCoins(amount...),
+	}
+}
+
+// MaxScheduleInstallments is the most installments that a schedule can have, whatever
+// the params, so that generating, storing and checking a schedule stays cheap
+const MaxScheduleInstallments int64 = 1000
+
+// ScheduleTerms describe how the repayment schedule of a debt is generated when the debt
+// is created. Equal and balloon schedules have Count installments, one every Interval
+// from the creation of the debt, and amortize the principal at the interest rate of the
+// debt. Custom schedules list their installments instead
+type ScheduleTerms struct {
+	Type         ScheduleType     `json:"type"`
+	Count        int64            `json:"count"`
+	Interval     time.Duration    `json:"interval"`
+	Installments []InstallmentDue `json:"installments"`
+}
+
+func NewScheduleTerms(scheduleType ScheduleType, count int64, interval time.Duration) ScheduleTerms {
+	return ScheduleTerms{
+		Type:     scheduleType,
+		Count:    count,
+		Interval: interval,
+	}
+}
+
+// NewCustomScheduleTerms yields the terms of a schedule with the given installments
+func NewCustomScheduleTerms(installments ...InstallmentDue) ScheduleTerms {
+	return ScheduleTerms{
+		Type:         ScheduleCustom,
+		Installments: installments,
+	}
+}
+
+// IsZero yields true if the terms generate no schedule
+func (terms ScheduleTerms) IsZero() bool {
+	return terms.Type == ScheduleNone
+}
+
+// InstallmentCount yields the number of installments of the schedule generated by the terms
+func (terms ScheduleTerms) InstallmentCount() int64 {
+	switch terms.Type {
+	case ScheduleEqual, ScheduleBalloon:
+		return terms.Count
+	case ScheduleCustom:
+		return int64(len(terms.Installments))
+	default:
+		return 0
+	}
+}
+
+// validateFor checks that the terms can generate the schedule of a debt
+// of the given amount, with the given interest terms
+func (terms ScheduleTerms) validateFor(amount DebtCoins, interest InterestTerms) error {
+	if !terms.Type.IsValid() {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, fmt.Sprintf("Unknown schedule type %s", terms.Type))
+	}
+
+	switch terms.Type {
+	case ScheduleNone:
+		return nil
+	case ScheduleCustom:
+		return terms.validateInstallments(amount)
+	}
+
+	if terms.Count <= 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Number of installments should be positive")
+	}
+
+	if terms.Count > MaxScheduleInstallments {
+		return sdkErr.Wrapf(ErrTooManyInstallments, "a schedule cannot have more than %d installments", MaxScheduleInstallments)
+	}
+
+	if terms.Interval <= 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interval between installments should be positive")
+	}
+
+	if len(terms.Installments) > 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Only custom schedules list their installments")
+	}
+
+	// the interest of each installment must match what accrues between installments
+	if !interest.IsZero() {
+		if interest.PeriodUnit != PeriodSeconds {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Amortized schedules need interest accruing by seconds")
+		}
+
+		if int64(terms.Interval/time.Second)%interest.Period != 0 {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Interval between installments should be a multiple of the interest period")
+		}
+	}
+
+	return nil
+}
+
+func (terms ScheduleTerms) validateInstallments(amount DebtCoins) error {
+	if len(terms.Installments) == 0 {
+		return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Custom schedules need installments")
+	}
+
+	if int64(len(terms.Installments)) > MaxScheduleInstallments {
+		return sdkErr.Wrapf(ErrTooManyInstallments, "a schedule cannot have more than %d installments", MaxScheduleInstallments)
+	}
+
+	total := amount.Zero()
+	for i, installment := range terms.Installments {
+		if installment.DueTime.IsZero() {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Due time of installments is missing")
+		}
+
+		if i > 0 && !installment.DueTime.After(terms.Installments[i-1].DueTime) {
+			return sdkErr.Wrap(sdkErr.ErrInvalidRequest, "Installments should be sorted by due time")
+		}
+
+		if len(installment.Amount) == 0 || installment.Amount.IsZero() {
+			return sdkErr.Wrap(sdkErr.ErrInvalidCoins, "Amount of installments is missing")
+		}
+
+		if err := installment.Amount.Validate(); err != nil {
+			return err
+		}
+
+		for _, coin := range installment.Amount {
+			if !amount.HasDenom(coin.Denom) {
+				return sdkErr.Wrapf(ErrDenomMismatch, "the debt is in %s, not %s", amount, coin.Denom)
+			}
+		}
+
+		total = total.Add(installment.Amount)
+	}
+
+	if !total.IsAllGTE(amount) {
+		return sdkErr.Wrapf(sdkErr.ErrInvalidRequest, "Installments should cover the whole amount %s", amount)
+	}
+
+	return nil
+}
+
+// Generate yields the schedule of a debt of the given principal and interest terms, created at the given time
+func (terms ScheduleTerms) Generate(principal DebtCoins, interest InterestTerms, start time.Time) Schedule {
+	switch terms.Type {
+	case ScheduleEqual, ScheduleBalloon:
+		return terms.amortize(principal, installmentRate(interest, terms.Interval), start)
+	case ScheduleCustom:
+		schedule := make(Schedule, len(terms.Installments))
+		for i, due := range terms.Installments {
+			schedule[i] = newInstallment(due.DueTime, nil, nil, due.Amount)
+		}
+		return schedule
+	default:
+		return nil
+	}
+}
+
+// amortize yields the installments that repay the principal at the given rate per installment
+func (terms ScheduleTerms) amortize(principal DebtCoins, rate sdk.Dec, start time.Time) Schedule {
+	principals := make([][]sdk.Coin, terms.Count)
+	interests := make([][]sdk.Coin, terms.Count)
+
+	for _, coin := range principal {
+		balance := coin.Amount
+		payment := equalPayment(balance, rate, terms.Count)
+
+		for i := int64(0); i < terms.Count; i++ {
+			interest := rate.MulInt(balance).TruncateInt()
+
+			var repaid sdk.Int
+			switch {
+			case i == terms.Count-1:
+				repaid = balance
+			case terms.Type == ScheduleBalloon:
+				repaid = sdk.ZeroInt()
+			default:
+				repaid = sdk.MinInt(payment.Sub(interest), balance)
+			}
+			balance = balance.Sub(repaid)
+
+			principals[i] = append(principals[i], sdk.NewCoin(coin.Denom, repaid))
+			interests[i] = append(interests[i], sdk.NewCoin(coin.Denom, interest))
+		}
+	}
+
+	schedule := make(Schedule, terms.Count)
+	for i := range schedule {
+		dueTime := start.Add(time.Duration(i+1) * terms.Interval)
+		schedule[i] = newInstallment(dueTime, NewDebtCoins(principals[i]...), NewDebtCoins(interests[i]...), nil)
+	}
+
+	return schedule
+}
+
+// equalPayment yields the installment that repays the principal in the given
+// number of equal installments, each charged the given rate on the balance
+func equalPayment(principal sdk.Int, rate sdk.Dec, count int64) sdk.Int {
+	if rate.IsZero() {
+		return principal.QuoRaw(count)
+	}
+
+	growth := sdk.OneDec().Add(rate).Power(uint64(count))
+	return rate.Mul(growth).MulInt(principal).Quo(growth.Sub(sdk.OneDec())).TruncateInt()
+}
+
+// installmentRate yields the interest rate that accrues with the given terms in the given time
+func installmentRate(terms InterestTerms, interval time.Duration) sdk.Dec {
+	if terms.IsZero() {
+		return sdk.ZeroDec()
+	}
+
+	periods := int64(interval/time.Second) / terms.Period
+	if terms.Method == InterestCompound {
+		return sdk.OneDec().Add(terms.Rate).Power(uint64(periods)).Sub(sdk.OneDec())
+	}
+
+	return terms.Rate.MulInt64(periods)
+}
+
+// Installment is a row of the repayment schedule of a debt. Amount is what is due
+// by DueTime: the principal and interest it amortizes, or a custom amount. What is
+// paid or forgiven of it is no longer Remaining
+type Installment struct {
+	DueTime   time.Time         `json:"due_time"`
+	Principal DebtCoins         `json:"principal"`
+	Interest  DebtCoins         `json:"interest"`
+	Amount    DebtCoins         `json:"amount"`
+	Paid      DebtCoins         `json:"paid"`
+	Forgiven  DebtCoins         `json:"forgiven"`
+	Remaining DebtCoins         `json:"remaining"`
+	Status    InstallmentStatus `json:"status"`
+}
+
+// newInstallment yields an unpaid installment of the given principal and interest,
+// or of the given amount if it does not amortize the principal
+func newInstallment(dueTime time.Time, principal, interest, amount DebtCoins) Installment {
+	if amount == nil {
+		amount = principal.Add(interest)
+	}
+
+	return Installment{
+		DueTime:   dueTime,
+		Principal: principal,
+		Interest:  interest,
+		Amount:    amount,
+		Paid:      amount.Zero(),
+		Forgiven:  amount.Zero(),
+		Remaining: amount,
+		Status:    InstallmentPending,
+	}
+}
+
+// IsSettled yields true if nothing remains of the installment
+func (i Installment) IsSettled() bool {
+	return i.Status == InstallmentPaid || i.Status == InstallmentForgiven
+}
+
+func (i Installment) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Due: %s
+Amount: %s
+Paid: %s
+Forgiven: %s
+Remaining: %s
+Status: %s`, i.DueTime, i.Amount, i.Paid, i.Forgiven, i.Remaining, i.Status))
+}
+
+// Schedule are the installments of a debt, sorted by due time
+type Schedule []Installment
+
+// Allocate yields the schedule after a payment of the given amount, that goes
+// to the earliest unpaid installments first
+func (s Schedule) Allocate(payment DebtCoins) Schedule {
+	if len(s) == 0 {
+		return s
+	}
+
+	allocated := make(Schedule, len(s))
+	copy(allocated, s)
+
+	for i, installment := range allocated {
+		if payment.IsZero() {
+			break
+		}
+		if installment.IsSettled() {
+			continue
+		}
+
+		paid := installment.Remaining.Min(payment)
+		payment = payment.Sub(paid)
+
+		installment.Paid = installment.Paid.Add(paid)
+		installment.Remaining = installment.Remaining.Sub(paid)
+		if installment.Remaining.IsZero() {
+			installment.Status = InstallmentPaid
+		}
+		allocated[i] = installment
+	}
+
+	return allocated
+}
+
+// Forgive yields the schedule after the creditor forgives the given amount, that comes
+// off the latest unsettled installments first, so that the debtor still owes the
+// earliest installments, in full, until what is left of the debt is covered
+func (s Schedule) Forgive(amount DebtCoins) Schedule {
+	if len(s) == 0 {
+		return s
+	}
+
+	forgiven := make(Schedule, len(s))
+	copy(forgiven, s)
+
+	for i := len(forgiven) - 1; i >= 0; i-- {
+		if amount.IsZero() {
+			break
+		}
+
+		installment := forgiven[i]
+		if installment.IsSettled() {
+			continue
+		}
+
+		off := installment.Remaining.Min(amount)
+		amount = amount.Sub(off)
+
+		if len(installment.Forgiven) == 0 {
+			installment.Forgiven = installment.Amount.Zero()
+		}
+		installment.Forgiven = installment.Forgiven.Add(off)
+		installment.Remaining = installment.Remaining.Sub(off)
+		if installment.Remaining.IsZero() {
+			installment.Status = InstallmentForgiven
+		}
+		forgiven[i] = installment
+	}
+
+	return forgiven
+}
+
+// LateAt yields the indexes of the pending installments that are late at the given time
+func (s Schedule) LateAt(blockTime time.Time) []int {
+	var late []int
+	for i, installment := range s {
+		if installment.Status == InstallmentPending && blockTime.After(installment.DueTime) {
+			late = append(late, i)
+		}
+	}
+
+	return late
+}
+
+// NextDueTime yields the earliest due time of the pending installments, after
+// which the first of them becomes late, or false if no installment is pending
+func (s Schedule) NextDueTime() (time.Time, bool) {
+	var next time.Time
+	pending := false
+	for _, installment := range s {
+		if installment.Status == InstallmentPending && (!pending || installment.DueTime.Before(next)) {
+			next, pending = installment.DueTime, true
+		}
+	}
+
+	return next, pending
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Settlement is how the balance of a debt has been settled so far
+type Settlement struct {
+	PrincipalPaid DebtCoins `json:"principal_paid"`
+	InterestPaid  DebtCoins `json:"interest_paid"`
+	Forgiven      DebtCoins `json:"forgiven"` // reduced by the creditor
+	FeesPaid      DebtCoins `json:"fees_paid"`
+}
+
+// NewSettlement yields the settlement of a debt of the given amount that has not been paid at all
+func NewSettlement(amount DebtCoins) Settlement {
+	return Settlement{
+		PrincipalPaid: amount.Zero(),
+		InterestPaid:  amount.Zero(),
+		Forgiven:      amount.Zero(),
+		FeesPaid:      amount.Zero(),
+	}
+}
+
+// IsZero yields true if nothing of the debt has been settled yet
+func (s Settlement) IsZero() bool {
+	return s.PrincipalPaid.IsZero() && s.InterestPaid.IsZero() && s.Forgiven.IsZero() && s.FeesPaid.IsZero()
+}
+
+// AddPaid yields the settlement after a payment of the given principal and interest.
+// Debts stored before settlements were tracked start from no coins at all
+func (s Settlement) AddPaid(principal, interest DebtCoins) Settlement {
+	s.PrincipalPaid = s.PrincipalPaid.Add(principal)
+	s.InterestPaid = s.InterestPaid.Add(interest)
+	return s
+}
+
+// AddForgiven yields the settlement after the creditor reduced the debt by the given amount
+func (s Settlement) AddForgiven(amount DebtCoins) Settlement {
+	s.Forgiven = s.Forgiven.Add(amount)
+	return s
+}
+
+// AddFeesPaid yields the settlement after a payment of the given late fees
+func (s Settlement) AddFeesPaid(fees DebtCoins) Settlement {
+	s.FeesPaid = s.FeesPaid.Add(fees)
+	return s
+}
+
+func (s Settlement) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Principal paid: %s
+Interest paid: %s
+Forgiven: %s
+Fees paid: %s`, s.PrincipalPaid, s.InterestPaid, s.Forgiven, s.FeesPaid))
+}
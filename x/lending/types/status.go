@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+
+	sdkErr "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// DebtStatus is the stage of the lifecycle of a debt
+type DebtStatus string
+
+const (
+	StatusActive     DebtStatus = "active"     // not yet due, or due without deadline
+	StatusOverdue    DebtStatus = "overdue"    // past its maturity, but still within its grace period
+	StatusDefaulted  DebtStatus = "defaulted"  // past its maturity and its grace period
+	StatusRepaid     DebtStatus = "repaid"     // fully paid by the debtor
+	StatusForgiven   DebtStatus = "forgiven"   // reduced to zero by the creditor
+	StatusLiquidated DebtStatus = "liquidated" // paid by a liquidator in exchange for its collateral
+)
+
+// DebtStatusFromString yields the status with the given name
+func DebtStatusFromString(str string) (DebtStatus, error) {
+	status := DebtStatus(str)
+	if !status.IsValid() {
+		return "", sdkErr.Wrap(sdkErr.ErrInvalidRequest, fmt.Sprintf("Unknown debt status %s", str))
+	}
+
+	return status, nil
+}
+
+func (status DebtStatus) IsValid() bool {
+	switch status {
+	case StatusActive, StatusOverdue, StatusDefaulted, StatusRepaid, StatusForgiven, StatusLiquidated:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsClosed yields true if nothing can be paid or changed anymore on a debt with this status
+func (status DebtStatus) IsClosed() bool {
+	return status == StatusRepaid || status == StatusForgiven || status == StatusLiquidated
+}
+
+func (status DebtStatus) String() string {
+	return string(status)
+}
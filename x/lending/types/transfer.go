@@ -0,0 +1,30 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// DebtTransfer is the assignment of a debt to a new creditor, that waits
+// for the consent of the debtor when the module params require it
+type DebtTransfer struct {
+	DebtID      string         `json:"debt_id"`
+	Creditor    sdk.AccAddress `json:"creditor"`
+	NewCreditor sdk.AccAddress `json:"new_creditor"`
+}
+
+func NewDebtTransfer(debtID string, creditor, newCreditor sdk.AccAddress) DebtTransfer {
+	return DebtTransfer{
+		DebtID:      debtID,
+		Creditor:    creditor,
+		NewCreditor: newCreditor,
+	}
+}
+
+func (t DebtTransfer) String() string {
+	return strings.TrimSpace(fmt.Sprintf(`Debt ID: %s
+Creditor: %s
+New creditor: %s`, t.DebtID, t.Creditor, t.NewCreditor))
+}